@@ -33,7 +33,13 @@ type Fingerprint struct {
 	HTML        interface{}            `json:"html"`
 	Script      interface{}            `json:"scripts"`
 	ScriptSrc   interface{}            `json:"scriptSrc"`
+	URL         interface{}            `json:"url"`
 	Meta        map[string]interface{} `json:"meta"`
+	DNS         map[string]interface{} `json:"dns"`
+	CertIssuer  interface{}            `json:"certIssuer"`
+	Robots      interface{}            `json:"robots"`
+	Text        interface{}            `json:"text"`
+	Probe       map[string]string      `json:"probe"`
 	Implies     interface{}            `json:"implies"`
 	Description string                 `json:"description"`
 	Website     string                 `json:"website"`
@@ -59,7 +65,13 @@ type OutputFingerprint struct {
 	HTML        []string                          `json:"html,omitempty"`
 	Script      []string                          `json:"scripts,omitempty"`
 	ScriptSrc   []string                          `json:"scriptSrc,omitempty"`
+	URL         []string                          `json:"url,omitempty"`
 	Meta        map[string][]string               `json:"meta,omitempty"`
+	DNS         map[string][]string               `json:"dns,omitempty"`
+	CertIssuer  []string                          `json:"certIssuer,omitempty"`
+	Robots      []string                          `json:"robots,omitempty"`
+	Text        []string                          `json:"text,omitempty"`
+	Probe       map[string]string                 `json:"probe,omitempty"`
 	Implies     []string                          `json:"implies,omitempty"`
 	Description string                            `json:"description,omitempty"`
 	Website     string                            `json:"website,omitempty"`
@@ -166,6 +178,8 @@ func normalizeFingerprints(fingerprints *Fingerprints) *OutputFingerprints {
 			Headers:     make(map[string]string),
 			JS:          make(map[string]string),
 			Meta:        make(map[string][]string),
+			DNS:         make(map[string][]string),
+			Probe:       make(map[string]string),
 			Description: fingerprint.Description,
 			Website:     fingerprint.Website,
 			CPE:         fingerprint.CPE,
@@ -178,6 +192,9 @@ func normalizeFingerprints(fingerprints *Fingerprints) *OutputFingerprints {
 		for k, v := range fingerprint.JS {
 			output.JS[k] = v
 		}
+		for path, pattern := range fingerprint.Probe {
+			output.Probe[path] = pattern
+		}
 
 		for header, pattern := range fingerprint.Headers {
 			output.Headers[strings.ToLower(header)] = strings.ToLower(pattern)
@@ -263,6 +280,25 @@ func normalizeFingerprints(fingerprints *Fingerprints) *OutputFingerprints {
 			sort.Strings(output.ScriptSrc)
 		}
 
+		// Use reflection type switch for determining URL type
+		if fingerprint.URL != nil {
+			v := reflect.ValueOf(fingerprint.URL)
+
+			switch v.Kind() {
+			case reflect.String:
+				data := v.Interface().(string)
+				output.URL = append(output.URL, data)
+			case reflect.Slice:
+				data := v.Interface().([]interface{})
+				for _, pattern := range data {
+					pat := pattern.(string)
+					output.URL = append(output.URL, pat)
+				}
+			}
+
+			sort.Strings(output.URL)
+		}
+
 		for header, pattern := range fingerprint.Meta {
 			v := reflect.ValueOf(pattern)
 
@@ -287,6 +323,83 @@ func normalizeFingerprints(fingerprints *Fingerprints) *OutputFingerprints {
 			}
 		}
 
+		// Use reflection type switch for determining CertIssuer type
+		if fingerprint.CertIssuer != nil {
+			v := reflect.ValueOf(fingerprint.CertIssuer)
+
+			switch v.Kind() {
+			case reflect.String:
+				data := v.Interface().(string)
+				output.CertIssuer = append(output.CertIssuer, data)
+			case reflect.Slice:
+				data := v.Interface().([]interface{})
+				for _, pattern := range data {
+					pat := pattern.(string)
+					output.CertIssuer = append(output.CertIssuer, pat)
+				}
+			}
+
+			sort.Strings(output.CertIssuer)
+		}
+
+		// Use reflection type switch for determining Robots type
+		if fingerprint.Robots != nil {
+			v := reflect.ValueOf(fingerprint.Robots)
+
+			switch v.Kind() {
+			case reflect.String:
+				data := v.Interface().(string)
+				output.Robots = append(output.Robots, data)
+			case reflect.Slice:
+				data := v.Interface().([]interface{})
+				for _, pattern := range data {
+					pat := pattern.(string)
+					output.Robots = append(output.Robots, pat)
+				}
+			}
+
+			sort.Strings(output.Robots)
+		}
+
+		// Use reflection type switch for determining Text type
+		if fingerprint.Text != nil {
+			v := reflect.ValueOf(fingerprint.Text)
+
+			switch v.Kind() {
+			case reflect.String:
+				data := v.Interface().(string)
+				output.Text = append(output.Text, data)
+			case reflect.Slice:
+				data := v.Interface().([]interface{})
+				for _, pattern := range data {
+					pat := pattern.(string)
+					output.Text = append(output.Text, pat)
+				}
+			}
+
+			sort.Strings(output.Text)
+		}
+
+		for record, pattern := range fingerprint.DNS {
+			v := reflect.ValueOf(pattern)
+
+			switch v.Kind() {
+			case reflect.String:
+				data := v.Interface().(string)
+				output.DNS[strings.ToUpper(record)] = []string{data}
+			case reflect.Slice:
+				data := v.Interface().([]interface{})
+
+				final := []string{}
+				for _, pattern := range data {
+					pat := pattern.(string)
+					final = append(final, pat)
+				}
+				sort.Strings(final)
+				output.DNS[strings.ToUpper(record)] = final
+			}
+		}
+
 		// Use reflection type switch for determining "Implies" tag type
 		if fingerprint.Implies != nil {
 			v := reflect.ValueOf(fingerprint.Implies)
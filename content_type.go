@@ -0,0 +1,115 @@
+package wappalyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// contentKind is the kind of a response body, deciding the checks run on it
+type contentKind int
+
+const (
+	// htmlContent bodies are tokenized and matched against the html patterns
+	htmlContent contentKind = iota
+	// scriptContent bodies are matched against the scripts patterns
+	scriptContent
+	// stylesheetContent bodies are matched against the css patterns
+	stylesheetContent
+	// dataContent bodies, such as JSON and XML documents, are not matched
+	dataContent
+	// binaryContent bodies, such as images and archives, are not matched
+	binaryContent
+)
+
+// sniffLen is the number of bytes looked at to find the root of XML documents
+const sniffLen = 1024
+
+// checkContent checks for fingerprints in the body of a response,
+// running the checks of the kind of its content type.
+func (s *Wappalyze) checkContent(contentType string, body []byte) ([]matchPartResult, bodyInfo, contentKind) {
+	kind := getContentKind(contentType, body)
+	switch kind {
+	case htmlContent:
+		technologies, info := s.checkBody(body)
+		return technologies, info, kind
+	case scriptContent:
+		return s.checkScriptContent(body), bodyInfo{}, kind
+	case stylesheetContent:
+		return s.checkStylesheetContent(body), bodyInfo{}, kind
+	}
+	return nil, bodyInfo{}, kind
+}
+
+// getContentKind returns the kind of a body from its content type,
+// sniffing it from the body if the content type is absent or unknown.
+func getContentKind(contentType string, body []byte) contentKind {
+	if kind, ok := parseContentKind(contentType); ok {
+		return kind
+	}
+	return sniffContentKind(body)
+}
+
+// parseContentKind returns the kind of a content type, if it's known
+func parseContentKind(contentType string) (contentKind, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, _, _ = strings.Cut(contentType, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	}
+
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		return htmlContent, true
+	case "text/javascript", "application/javascript", "application/x-javascript",
+		"application/ecmascript", "text/ecmascript":
+		return scriptContent, true
+	case "text/css":
+		return stylesheetContent, true
+	case "application/json", "text/json", "application/xml", "text/xml":
+		return dataContent, true
+	case "application/octet-stream", "application/pdf", "application/zip",
+		"application/gzip", "application/wasm":
+		return binaryContent, true
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "video/"), strings.HasPrefix(mediaType, "font/"):
+		return binaryContent, true
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return dataContent, true
+	}
+	return htmlContent, false
+}
+
+// sniffContentKind returns the kind of a body from its contents. Text that
+// isn't recognized as JSON is handled as HTML, as servers often omit the
+// content type of their pages, and so are XML documents with an html root
+// such as XHTML pages.
+func sniffContentKind(body []byte) contentKind {
+	if len(body) == 0 {
+		return htmlContent
+	}
+
+	contentType := http.DetectContentType(body)
+	if strings.HasPrefix(contentType, "text/xml") {
+		if bytes.Contains(bytes.ToLower(body[:min(len(body), sniffLen)]), []byte("<html")) {
+			return htmlContent
+		}
+		return dataContent
+	}
+	if strings.HasPrefix(contentType, "text/plain") {
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+			return dataContent
+		}
+		return htmlContent
+	}
+	if kind, ok := parseContentKind(contentType); ok {
+		return kind
+	}
+	return binaryContent
+}
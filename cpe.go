@@ -0,0 +1,398 @@
+package wappalyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CPE is a Common Platform Enumeration 2.3 name.
+//
+// Attributes are kept in their formatted string form, as they appear in
+// "cpe:2.3:" names: special characters are quoted with a backslash,
+// unquoted '*' and '?' are wildcards, and the logical values ANY and
+// NA are CPEAny and CPENotApplicable.
+type CPE struct {
+	Part      string
+	Vendor    string
+	Product   string
+	Version   string
+	Update    string
+	Edition   string
+	Language  string
+	SWEdition string
+	TargetSW  string
+	TargetHW  string
+	Other     string
+}
+
+const (
+	// CPEAny is the logical value matching any value of an attribute
+	CPEAny = "*"
+	// CPENotApplicable is the logical value for attributes which do not apply
+	CPENotApplicable = "-"
+
+	cpeFormattedPrefix = "cpe:2.3:"
+	cpeURIPrefix       = "cpe:/"
+)
+
+// ParseCPE parses a CPE name in either the 2.3 formatted string
+// binding (cpe:2.3:...) or the URI binding (cpe:/...).
+func ParseCPE(name string) (*CPE, error) {
+	switch {
+	case strings.HasPrefix(strings.ToLower(name), cpeFormattedPrefix):
+		return parseFormattedCPE(name)
+	case strings.HasPrefix(strings.ToLower(name), cpeURIPrefix):
+		return parseURICPE(name)
+	}
+	return nil, fmt.Errorf("invalid cpe: %q", name)
+}
+
+func parseFormattedCPE(name string) (*CPE, error) {
+	fields := splitUnquoted(name[len(cpeFormattedPrefix):], ':')
+	if len(fields) != 11 {
+		return nil, fmt.Errorf("invalid cpe: %q: expected 11 attributes, got %d", name, len(fields))
+	}
+	for _, field := range fields {
+		if field == "" {
+			return nil, fmt.Errorf("invalid cpe: %q: empty attribute", name)
+		}
+	}
+
+	cpe := &CPE{}
+	for i, attribute := range cpe.attributes() {
+		*attribute = fields[i]
+	}
+	if err := cpe.validatePart(); err != nil {
+		return nil, fmt.Errorf("invalid cpe: %q: %w", name, err)
+	}
+	return cpe, nil
+}
+
+func parseURICPE(name string) (*CPE, error) {
+	components := strings.Split(name[len(cpeURIPrefix):], ":")
+	if len(components) > 7 {
+		return nil, fmt.Errorf("invalid cpe: %q: too many components", name)
+	}
+
+	cpe := &CPE{}
+	attributes := cpe.attributes()
+	for i := range attributes {
+		*attributes[i] = CPEAny
+	}
+	for i, component := range components {
+		// Extended attributes are packed in the edition component
+		if i == 5 && strings.HasPrefix(component, "~") {
+			packed := strings.Split(component, "~")
+			if len(packed) != 6 {
+				return nil, fmt.Errorf("invalid cpe: %q: invalid packed edition", name)
+			}
+			extended := []*string{&cpe.Edition, &cpe.SWEdition, &cpe.TargetSW, &cpe.TargetHW, &cpe.Other}
+			for j, value := range packed[1:] {
+				decoded, err := decodeURIValue(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid cpe: %q: %w", name, err)
+				}
+				*extended[j] = decoded
+			}
+			continue
+		}
+
+		decoded, err := decodeURIValue(component)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpe: %q: %w", name, err)
+		}
+		*attributes[i] = decoded
+	}
+	if err := cpe.validatePart(); err != nil {
+		return nil, fmt.Errorf("invalid cpe: %q: %w", name, err)
+	}
+	return cpe, nil
+}
+
+func (c *CPE) validatePart() error {
+	switch c.Part {
+	case "a", "o", "h", CPEAny:
+		return nil
+	}
+	return fmt.Errorf("invalid part %q", c.Part)
+}
+
+// attributes returns pointers to the attributes in binding order.
+func (c *CPE) attributes() []*string {
+	return []*string{
+		&c.Part, &c.Vendor, &c.Product, &c.Version, &c.Update, &c.Edition,
+		&c.Language, &c.SWEdition, &c.TargetSW, &c.TargetHW, &c.Other,
+	}
+}
+
+// String returns the CPE 2.3 formatted string binding of the name.
+func (c *CPE) String() string {
+	attributes := c.attributes()
+	values := make([]string, len(attributes))
+	for i, attribute := range attributes {
+		values[i] = *attribute
+		if values[i] == "" {
+			values[i] = CPEAny
+		}
+	}
+	return cpeFormattedPrefix + strings.Join(values, ":")
+}
+
+// URI returns the URI binding of the name.
+func (c *CPE) URI() string {
+	components := []string{
+		encodeURIValue(c.Part),
+		encodeURIValue(c.Vendor),
+		encodeURIValue(c.Product),
+		encodeURIValue(c.Version),
+		encodeURIValue(c.Update),
+		encodeURIValue(c.Edition),
+		encodeURIValue(c.Language),
+	}
+	if !isAnyValue(c.SWEdition) || !isAnyValue(c.TargetSW) || !isAnyValue(c.TargetHW) || !isAnyValue(c.Other) {
+		components[5] = "~" + strings.Join([]string{
+			encodeURIValue(c.Edition),
+			encodeURIValue(c.SWEdition),
+			encodeURIValue(c.TargetSW),
+			encodeURIValue(c.TargetHW),
+			encodeURIValue(c.Other),
+		}, "~")
+	}
+
+	// Trailing ANY components are omitted
+	end := len(components)
+	for end > 0 && components[end-1] == "" {
+		end--
+	}
+	return cpeURIPrefix + strings.Join(components[:end], ":")
+}
+
+// WithVersion returns a copy of the name with the version attribute
+// set to version, quoting it as required.
+func (c *CPE) WithVersion(version string) *CPE {
+	versioned := *c
+	versioned.Version = EscapeCPEValue(version)
+	if versioned.Version == "" {
+		versioned.Version = CPEAny
+	}
+	return &versioned
+}
+
+// Matches reports whether the name, used as a source pattern, matches
+// target. ANY matches any value, NA only matches NA and other values
+// are compared case-insensitively, with support for wildcards.
+func (c *CPE) Matches(target *CPE) bool {
+	sources := c.attributes()
+	targets := target.attributes()
+	for i := range sources {
+		if !matchCPEValue(*sources[i], *targets[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchCPEValue(source, target string) bool {
+	switch {
+	case isAnyValue(source):
+		return true
+	case source == CPENotApplicable || target == CPENotApplicable:
+		return source == target
+	case isAnyValue(target):
+		return false
+	}
+	return matchCPEWildcard([]rune(source), []rune(unquoteCPEValue(target)))
+}
+
+// matchCPEWildcard matches a quoted source value with '*' and '?'
+// wildcards against an unquoted target value.
+func matchCPEWildcard(source, target []rune) bool {
+	for len(source) > 0 {
+		switch source[0] {
+		case '*':
+			for i := 0; i <= len(target); i++ {
+				if matchCPEWildcard(source[1:], target[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(target) == 0 {
+				return false
+			}
+			source, target = source[1:], target[1:]
+			continue
+		case '\\':
+			if len(source) > 1 {
+				source = source[1:]
+			}
+		}
+		if len(target) == 0 || unicode.ToLower(source[0]) != unicode.ToLower(target[0]) {
+			return false
+		}
+		source, target = source[1:], target[1:]
+	}
+	return len(target) == 0
+}
+
+// EscapeCPEValue quotes a plain value for use as a formatted string
+// attribute. Spaces are not allowed in names and become underscores.
+func EscapeCPEValue(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		switch {
+		case r == ' ':
+			builder.WriteByte('_')
+		case r < unicode.MaxASCII && !isAlphanumeric(byte(r)) && r != '_' && r != '.' && r != '-':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// unquoteCPEValue removes the quoting from a formatted string attribute.
+func unquoteCPEValue(value string) string {
+	return unescapeLiteral(value)
+}
+
+func isAnyValue(value string) bool {
+	return value == "" || value == CPEAny
+}
+
+// splitUnquoted splits s on separators not quoted with a backslash.
+func splitUnquoted(s string, separator byte) []string {
+	var fields []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case separator:
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, s[start:])
+}
+
+// encodeURIValue converts a formatted string attribute into
+// its percent-encoded URI binding form.
+func encodeURIValue(value string) string {
+	switch value {
+	case "", CPEAny:
+		return ""
+	case CPENotApplicable:
+		return CPENotApplicable
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\' && i+1 < len(value):
+			i++
+			next := value[i]
+			if next == '.' || next == '-' || next == '_' {
+				builder.WriteByte(next)
+			} else {
+				fmt.Fprintf(&builder, "%%%02x", next)
+			}
+		case c == '?':
+			builder.WriteString("%01")
+		case c == '*':
+			builder.WriteString("%02")
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return builder.String()
+}
+
+// decodeURIValue converts a URI binding component into
+// its formatted string attribute form.
+func decodeURIValue(value string) (string, error) {
+	switch value {
+	case "":
+		return CPEAny, nil
+	case CPENotApplicable:
+		return CPENotApplicable, nil
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '%' {
+			if c < unicode.MaxASCII && !isAlphanumeric(c) && c != '_' && c != '.' && c != '-' {
+				builder.WriteByte('\\')
+			}
+			builder.WriteByte(c)
+			continue
+		}
+		if i+2 >= len(value) {
+			return "", fmt.Errorf("invalid percent encoding in %q", value)
+		}
+
+		parsed, err := strconv.ParseUint(value[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("invalid percent encoding in %q", value)
+		}
+		decoded := byte(parsed)
+		i += 2
+		switch {
+		case decoded == 0x01:
+			builder.WriteByte('?')
+		case decoded == 0x02:
+			builder.WriteByte('*')
+		case isAlphanumeric(decoded) || decoded == '_' || decoded == '.' || decoded == '-':
+			builder.WriteByte(decoded)
+		default:
+			builder.WriteByte('\\')
+			builder.WriteByte(decoded)
+		}
+	}
+	return builder.String(), nil
+}
+
+// VersionedCPE returns the CPE 2.3 formatted string of cpe with its
+// version attribute set to the detected version.
+func VersionedCPE(cpe, version string) (string, error) {
+	parsed, err := ParseCPE(cpe)
+	if err != nil {
+		return "", err
+	}
+	return parsed.WithVersion(version).String(), nil
+}
+
+// FingerprintWithCPE identifies technologies on a target,
+// based on the received response headers and body.
+// It returns the CPE of each detected technology that has one, with
+// the version attribute set to the detected version if there's any.
+//
+// Body should not be mutated while this function is being called, or it may
+// lead to unexpected things.
+func (s *Wappalyze) FingerprintWithCPE(headers map[string][]string, body []byte) map[string]*CPE {
+	apps := s.Fingerprint(headers, body)
+	result := make(map[string]*CPE, len(apps))
+
+	for app := range apps {
+		name, version, _ := strings.Cut(app, versionSeparator)
+		fingerprint, ok := s.fingerprints.Apps[name]
+		if !ok || fingerprint.cpe == "" {
+			continue
+		}
+		cpe, err := ParseCPE(fingerprint.cpe)
+		if err != nil {
+			continue
+		}
+		if version != "" {
+			cpe = cpe.WithVersion(version)
+		}
+		result[app] = cpe
+	}
+	return result
+}
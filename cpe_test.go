@@ -0,0 +1,99 @@
+package wappalyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCPE(t *testing.T) {
+	t.Run("formatted string", func(t *testing.T) {
+		cpe, err := ParseCPE("cpe:2.3:a:yahoo\\!:ui_library:2.8.0r4:*:*:*:*:*:*:*")
+		require.NoError(t, err)
+		require.Equal(t, "a", cpe.Part)
+		require.Equal(t, "yahoo\\!", cpe.Vendor)
+		require.Equal(t, "ui_library", cpe.Product)
+		require.Equal(t, "2.8.0r4", cpe.Version)
+		require.Equal(t, CPEAny, cpe.Other)
+		require.Equal(t, "cpe:2.3:a:yahoo\\!:ui_library:2.8.0r4:*:*:*:*:*:*:*", cpe.String())
+		require.Equal(t, "cpe:/a:yahoo%21:ui_library:2.8.0r4", cpe.URI())
+	})
+
+	t.Run("uri", func(t *testing.T) {
+		cpe, err := ParseCPE("cpe:/a:microsoft:internet_explorer:8.%02:sp%01:~~~-~x64~")
+		require.NoError(t, err)
+		require.Equal(t, "8.*", cpe.Version)
+		require.Equal(t, "sp?", cpe.Update)
+		require.Equal(t, CPEAny, cpe.Edition)
+		require.Equal(t, CPENotApplicable, cpe.TargetSW)
+		require.Equal(t, "x64", cpe.TargetHW)
+		require.Equal(t, "cpe:2.3:a:microsoft:internet_explorer:8.*:sp?:*:*:*:-:x64:*", cpe.String())
+		require.Equal(t, "cpe:/a:microsoft:internet_explorer:8.%02:sp%01:~~~-~x64~", cpe.URI())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{
+			"",
+			"cpe:2.3:a:vendor:product",
+			"cpe:2.3:x:vendor:product:*:*:*:*:*:*:*:*",
+			"cpe:2.3:a:vendor::*:*:*:*:*:*:*:*",
+			"cpe:/a:vendor:product:%zz",
+		} {
+			_, err := ParseCPE(input)
+			require.Error(t, err, "could parse invalid cpe %q", input)
+		}
+	})
+}
+
+func TestVersionedCPE(t *testing.T) {
+	cpe, err := VersionedCPE("cpe:2.3:a:wordpress:wordpress:*:*:*:*:*:*:*:*", "6.4.2")
+	require.NoError(t, err)
+	require.Equal(t, "cpe:2.3:a:wordpress:wordpress:6.4.2:*:*:*:*:*:*:*", cpe)
+
+	cpe, err = VersionedCPE("cpe:2.3:a:vendor:product:*:*:*:*:*:*:*:*", "1.0 (build:5)")
+	require.NoError(t, err)
+	require.Equal(t, "cpe:2.3:a:vendor:product:1.0_\\(build\\:5\\):*:*:*:*:*:*:*", cpe)
+
+	parsed, err := ParseCPE(cpe)
+	require.NoError(t, err, "could not parse escaped cpe")
+	require.Equal(t, "1.0_\\(build\\:5\\)", parsed.Version)
+}
+
+func TestCPEMatches(t *testing.T) {
+	tests := []struct {
+		source, target string
+		expected       bool
+	}{
+		{"cpe:2.3:a:wordpress:wordpress:*:*:*:*:*:*:*:*", "cpe:2.3:a:wordpress:wordpress:6.4.2:*:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:WordPress:WordPress:6.4.2:*:*:*:*:*:*:*", "cpe:2.3:a:wordpress:wordpress:6.4.2:*:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:wordpress:wordpress:6.4.2:*:*:*:*:*:*:*", "cpe:2.3:a:wordpress:wordpress:6.4.3:*:*:*:*:*:*:*", false},
+		{"cpe:2.3:a:wordpress:wordpress:6.4.*:*:*:*:*:*:*:*", "cpe:2.3:a:wordpress:wordpress:6.4.3:*:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:wordpress:wordpress:6.?.3:*:*:*:*:*:*:*", "cpe:2.3:a:wordpress:wordpress:6.4.3:*:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:wordpress:wordpress:6.4.2:*:*:*:*:*:*:*", "cpe:2.3:a:wordpress:wordpress:*:*:*:*:*:*:*:*", false},
+		{"cpe:2.3:a:vendor:product:1\\*:*:*:*:*:*:*:*", "cpe:2.3:a:vendor:product:1\\*:*:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:vendor:product:1\\*:*:*:*:*:*:*:*", "cpe:2.3:a:vendor:product:12:*:*:*:*:*:*:*", false},
+		{"cpe:2.3:a:vendor:product:*:-:*:*:*:*:*:*", "cpe:2.3:a:vendor:product:1.0:-:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:vendor:product:*:-:*:*:*:*:*:*", "cpe:2.3:a:vendor:product:1.0:sp1:*:*:*:*:*:*", false},
+		{"cpe:2.3:o:vendor:product:*:*:*:*:*:*:*:*", "cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source+"_"+tt.target, func(t *testing.T) {
+			source, err := ParseCPE(tt.source)
+			require.NoError(t, err)
+			target, err := ParseCPE(tt.target)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, source.Matches(target))
+		})
+	}
+}
+
+func TestFingerprintWithCPE(t *testing.T) {
+	wappalyzer, err := New()
+	require.Nil(t, err, "could not create wappalyzer")
+
+	matches := wappalyzer.FingerprintWithCPE(map[string][]string{
+		"liferay-portal": {"testserver 7.3.5"},
+	}, []byte(""))
+	require.Contains(t, matches, "Liferay:7.3.5", "Could not get correct match")
+	require.Equal(t, "cpe:2.3:a:liferay:liferay_portal:7.3.5:*:*:*:*:*:*:*", matches["Liferay:7.3.5"].String())
+}
@@ -0,0 +1,336 @@
+package wappalyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CVEDatabase is an offline index of CVEs loaded from a NVD JSON feed,
+// used to map detected technologies to candidate vulnerabilities.
+type CVEDatabase struct {
+	// criteria is organized as <vendor:product, match criteria>
+	criteria map[string][]*cveCriteria
+	// wildcards contains criteria with wildcards in vendor or product
+	wildcards []*cveCriteria
+	cves      map[string]*CVE
+}
+
+// CVE contains basic information about a vulnerability.
+type CVE struct {
+	ID          string
+	Description string
+	Severity    string
+	Score       float64
+	Published   string
+}
+
+// CVEMatch is a CVE matched for a technology along with the
+// vulnerable CPE match criteria it was matched by.
+type CVEMatch struct {
+	CVE      *CVE
+	Criteria string
+}
+
+// cveCriteria is a vulnerable CPE match criteria with its version range.
+type cveCriteria struct {
+	cve      *CVE
+	criteria string
+	cpe      *CPE
+
+	versionStartIncluding string
+	versionStartExcluding string
+	versionEndIncluding   string
+	versionEndExcluding   string
+}
+
+// nvdFeed is the NVD CVE API 2.0 response format.
+type nvdFeed struct {
+	Vulnerabilities []nvdVulnerability `json:"vulnerabilities"`
+}
+
+type nvdVulnerability struct {
+	CVE nvdCVE `json:"cve"`
+}
+
+type nvdCVE struct {
+	ID           string `json:"id"`
+	Published    string `json:"published"`
+	Descriptions []struct {
+		Lang  string `json:"lang"`
+		Value string `json:"value"`
+	} `json:"descriptions"`
+	Metrics        map[string][]nvdMetric `json:"metrics"`
+	Configurations []struct {
+		Nodes []nvdNode `json:"nodes"`
+	} `json:"configurations"`
+}
+
+type nvdMetric struct {
+	BaseSeverity string `json:"baseSeverity"`
+	CVSSData     struct {
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+}
+
+type nvdNode struct {
+	Negate   bool `json:"negate"`
+	CPEMatch []struct {
+		Vulnerable            bool   `json:"vulnerable"`
+		Criteria              string `json:"criteria"`
+		VersionStartIncluding string `json:"versionStartIncluding"`
+		VersionStartExcluding string `json:"versionStartExcluding"`
+		VersionEndIncluding   string `json:"versionEndIncluding"`
+		VersionEndExcluding   string `json:"versionEndExcluding"`
+	} `json:"cpeMatch"`
+}
+
+// cvssMetrics are the NVD metric types by order of preference
+var cvssMetrics = []string{"cvssMetricV40", "cvssMetricV31", "cvssMetricV30", "cvssMetricV2"}
+
+// LoadCVEDatabase loads a CVE database from a NVD JSON feed file.
+func LoadCVEDatabase(filePath string) (*CVEDatabase, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewCVEDatabase(f)
+}
+
+// NewCVEDatabase creates a CVE database from a NVD JSON feed.
+//
+// Both the NVD CVE API 2.0 format and trimmed exports are accepted,
+// trimmed exports being a JSON array of its vulnerabilities or of
+// their "cve" objects.
+func NewCVEDatabase(reader io.Reader) (*CVEDatabase, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var cves []nvdCVE
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			var vulnerability struct {
+				CVE *nvdCVE `json:"cve"`
+			}
+			if err := json.Unmarshal(item, &vulnerability); err != nil {
+				return nil, err
+			}
+			if vulnerability.CVE != nil {
+				cves = append(cves, *vulnerability.CVE)
+				continue
+			}
+			var cve nvdCVE
+			if err := json.Unmarshal(item, &cve); err != nil {
+				return nil, err
+			}
+			cves = append(cves, cve)
+		}
+	} else {
+		var feed nvdFeed
+		if err := json.Unmarshal(data, &feed); err != nil {
+			return nil, err
+		}
+		for _, vulnerability := range feed.Vulnerabilities {
+			cves = append(cves, vulnerability.CVE)
+		}
+	}
+
+	database := &CVEDatabase{
+		criteria: make(map[string][]*cveCriteria),
+		cves:     make(map[string]*CVE),
+	}
+	for _, cve := range cves {
+		if cve.ID == "" {
+			return nil, fmt.Errorf("cve without id in feed")
+		}
+		database.add(cve)
+	}
+	return database, nil
+}
+
+// add indexes the vulnerable match criteria of a CVE.
+func (d *CVEDatabase) add(item nvdCVE) {
+	cve := &CVE{
+		ID:        item.ID,
+		Published: item.Published,
+	}
+	for _, description := range item.Descriptions {
+		if description.Lang == "en" {
+			cve.Description = description.Value
+			break
+		}
+	}
+	for _, metricType := range cvssMetrics {
+		metrics := item.Metrics[metricType]
+		if len(metrics) == 0 {
+			continue
+		}
+		cve.Score = metrics[0].CVSSData.BaseScore
+		cve.Severity = metrics[0].CVSSData.BaseSeverity
+		if cve.Severity == "" {
+			cve.Severity = metrics[0].BaseSeverity
+		}
+		break
+	}
+	d.cves[cve.ID] = cve
+
+	for _, configuration := range item.Configurations {
+		for _, node := range configuration.Nodes {
+			if node.Negate {
+				continue
+			}
+			for _, match := range node.CPEMatch {
+				if !match.Vulnerable {
+					continue
+				}
+				cpe, err := ParseCPE(match.Criteria)
+				if err != nil {
+					continue
+				}
+
+				criteria := &cveCriteria{
+					cve:                   cve,
+					criteria:              match.Criteria,
+					cpe:                   cpe,
+					versionStartIncluding: match.VersionStartIncluding,
+					versionStartExcluding: match.VersionStartExcluding,
+					versionEndIncluding:   match.VersionEndIncluding,
+					versionEndExcluding:   match.VersionEndExcluding,
+				}
+				if strings.ContainsAny(cpe.Vendor+cpe.Product, "*?") {
+					d.wildcards = append(d.wildcards, criteria)
+					continue
+				}
+				key := cveIndexKey(cpe)
+				d.criteria[key] = append(d.criteria[key], criteria)
+			}
+		}
+	}
+}
+
+func cveIndexKey(cpe *CPE) string {
+	return strings.ToLower(unquoteCPEValue(cpe.Vendor) + ":" + unquoteCPEValue(cpe.Product))
+}
+
+// Len returns the number of CVEs in the database
+func (d *CVEDatabase) Len() int {
+	return len(d.cves)
+}
+
+// Get returns a CVE by its ID
+func (d *CVEDatabase) Get(id string) (*CVE, bool) {
+	cve, ok := d.cves[id]
+	return cve, ok
+}
+
+// Match returns the CVEs with a vulnerable match criteria matching cpe.
+//
+// CVEs that are only vulnerable in combination with another platform are
+// returned as well, as the platform can not be known from a single name.
+// Version ranges can only match names with a version.
+func (d *CVEDatabase) Match(cpe *CPE) []CVEMatch {
+	var matches []CVEMatch
+	seen := make(map[string]struct{})
+
+	check := func(criteria *cveCriteria) {
+		if _, ok := seen[criteria.cve.ID]; ok {
+			return
+		}
+		if !criteria.matches(cpe) {
+			return
+		}
+		seen[criteria.cve.ID] = struct{}{}
+		matches = append(matches, CVEMatch{CVE: criteria.cve, Criteria: criteria.criteria})
+	}
+	for _, criteria := range d.criteria[cveIndexKey(cpe)] {
+		check(criteria)
+	}
+	for _, criteria := range d.wildcards {
+		check(criteria)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].CVE.ID < matches[j].CVE.ID
+	})
+	return matches
+}
+
+// MatchString is like Match but accepts a CPE name in any binding.
+func (d *CVEDatabase) MatchString(cpe string) ([]CVEMatch, error) {
+	parsed, err := ParseCPE(cpe)
+	if err != nil {
+		return nil, err
+	}
+	return d.Match(parsed), nil
+}
+
+// MatchFingerprints returns the candidate CVEs for the technologies
+// detected by a Fingerprint call, keyed like the detections.
+// Technologies without a CPE in the fingerprints are skipped.
+func (d *CVEDatabase) MatchFingerprints(wappalyzer *Wappalyze, apps map[string]struct{}) map[string][]CVEMatch {
+	result := make(map[string][]CVEMatch)
+	for app := range apps {
+		name, version, _ := strings.Cut(app, versionSeparator)
+		fingerprint, ok := wappalyzer.fingerprints.Apps[name]
+		if !ok || fingerprint.cpe == "" {
+			continue
+		}
+		cpe, err := ParseCPE(fingerprint.cpe)
+		if err != nil {
+			continue
+		}
+		if version != "" {
+			cpe = cpe.WithVersion(version)
+		}
+
+		if matches := d.Match(cpe); len(matches) > 0 {
+			result[app] = matches
+		}
+	}
+	return result
+}
+
+// matches reports whether the criteria and its version range match cpe.
+func (c *cveCriteria) matches(cpe *CPE) bool {
+	if !c.hasRange() {
+		return c.cpe.Matches(cpe)
+	}
+
+	// The range replaces the version attribute of the criteria
+	criteria := *c.cpe
+	criteria.Version = CPEAny
+	if !criteria.Matches(cpe) || isAnyValue(cpe.Version) || cpe.Version == CPENotApplicable {
+		return false
+	}
+
+	version := unquoteCPEValue(cpe.Version)
+	switch {
+	case c.versionStartIncluding != "" && CompareVersions(version, c.versionStartIncluding) < 0:
+		return false
+	case c.versionStartExcluding != "" && CompareVersions(version, c.versionStartExcluding) <= 0:
+		return false
+	case c.versionEndIncluding != "" && CompareVersions(version, c.versionEndIncluding) > 0:
+		return false
+	case c.versionEndExcluding != "" && CompareVersions(version, c.versionEndExcluding) >= 0:
+		return false
+	}
+	return true
+}
+
+func (c *cveCriteria) hasRange() bool {
+	return c.versionStartIncluding != "" || c.versionStartExcluding != "" ||
+		c.versionEndIncluding != "" || c.versionEndExcluding != ""
+}
@@ -0,0 +1,131 @@
+package wappalyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testNVDFeed = `{
+	"resultsPerPage": 3,
+	"format": "NVD_CVE",
+	"version": "2.0",
+	"vulnerabilities": [
+		{
+			"cve": {
+				"id": "CVE-2023-0001",
+				"published": "2023-01-01T00:00:00.000",
+				"descriptions": [{"lang": "en", "value": "Range vulnerability."}],
+				"metrics": {
+					"cvssMetricV31": [{"cvssData": {"baseScore": 9.8, "baseSeverity": "CRITICAL"}}]
+				},
+				"configurations": [{"nodes": [{"operator": "OR", "negate": false, "cpeMatch": [
+					{"vulnerable": true, "criteria": "cpe:2.3:a:liferay:liferay_portal:*:*:*:*:*:*:*:*", "versionStartIncluding": "7.0.0", "versionEndExcluding": "7.4.0"}
+				]}]}]
+			}
+		},
+		{
+			"cve": {
+				"id": "CVE-2023-0002",
+				"descriptions": [{"lang": "en", "value": "Exact version vulnerability."}],
+				"metrics": {
+					"cvssMetricV2": [{"baseSeverity": "MEDIUM", "cvssData": {"baseScore": 5.0}}]
+				},
+				"configurations": [{"nodes": [{"operator": "OR", "negate": false, "cpeMatch": [
+					{"vulnerable": true, "criteria": "cpe:2.3:a:liferay:liferay_portal:7.3.5:*:*:*:*:*:*:*"},
+					{"vulnerable": false, "criteria": "cpe:2.3:o:linux:linux_kernel:*:*:*:*:*:*:*:*"}
+				]}]}]
+			}
+		},
+		{
+			"cve": {
+				"id": "CVE-2023-0003",
+				"descriptions": [{"lang": "en", "value": "Fixed vulnerability."}],
+				"configurations": [{"nodes": [{"operator": "OR", "negate": false, "cpeMatch": [
+					{"vulnerable": true, "criteria": "cpe:2.3:a:liferay:liferay_portal:*:*:*:*:*:*:*:*", "versionEndIncluding": "7.2.1"}
+				]}]}]
+			}
+		}
+	]
+}`
+
+func TestCVEDatabase(t *testing.T) {
+	database, err := NewCVEDatabase(strings.NewReader(testNVDFeed))
+	require.NoError(t, err, "could not load cve database")
+	require.Equal(t, 3, database.Len())
+
+	cve, ok := database.Get("CVE-2023-0001")
+	require.True(t, ok, "could not get cve")
+	require.Equal(t, "Range vulnerability.", cve.Description)
+	require.Equal(t, "CRITICAL", cve.Severity)
+	require.Equal(t, 9.8, cve.Score)
+
+	cve, _ = database.Get("CVE-2023-0002")
+	require.Equal(t, "MEDIUM", cve.Severity, "could not get cvss v2 severity")
+
+	tests := []struct {
+		cpe      string
+		expected []string
+	}{
+		{"cpe:2.3:a:liferay:liferay_portal:7.3.5:*:*:*:*:*:*:*", []string{"CVE-2023-0001", "CVE-2023-0002"}},
+		{"cpe:2.3:a:liferay:liferay_portal:7.0.0:*:*:*:*:*:*:*", []string{"CVE-2023-0001", "CVE-2023-0003"}},
+		{"cpe:2.3:a:liferay:liferay_portal:7.4.0:*:*:*:*:*:*:*", nil},
+		{"cpe:2.3:a:liferay:liferay_portal:*:*:*:*:*:*:*:*", nil},
+		{"cpe:/a:liferay:liferay_portal:6.2", []string{"CVE-2023-0003"}},
+		{"cpe:2.3:o:linux:linux_kernel:5.0:*:*:*:*:*:*:*", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cpe, func(t *testing.T) {
+			matches, err := database.MatchString(tt.cpe)
+			require.NoError(t, err)
+
+			var ids []string
+			for _, match := range matches {
+				ids = append(ids, match.CVE.ID)
+			}
+			require.Equal(t, tt.expected, ids)
+		})
+	}
+
+	t.Run("fingerprints", func(t *testing.T) {
+		wappalyzer, err := New()
+		require.Nil(t, err, "could not create wappalyzer")
+
+		apps := wappalyzer.Fingerprint(map[string][]string{
+			"liferay-portal": {"testserver 7.3.5"},
+		}, []byte(""))
+		matches := database.MatchFingerprints(wappalyzer, apps)
+		require.Len(t, matches["Liferay:7.3.5"], 2, "could not get cves for detection")
+		require.Equal(t, "cpe:2.3:a:liferay:liferay_portal:*:*:*:*:*:*:*:*", matches["Liferay:7.3.5"][0].Criteria)
+	})
+}
+
+func TestLoadCVEDatabase(t *testing.T) {
+	t.Run("trimmed", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "cves.json")
+		err := os.WriteFile(filePath, []byte(`[
+			{"id": "CVE-2023-0004", "configurations": [{"nodes": [{"cpeMatch": [
+				{"vulnerable": true, "criteria": "cpe:2.3:a:jquery:jquery:*:*:*:*:*:*:*:*", "versionEndExcluding": "3.5.0"}
+			]}]}]},
+			{"cve": {"id": "CVE-2023-0005"}}
+		]`), 0o600)
+		require.NoError(t, err)
+
+		database, err := LoadCVEDatabase(filePath)
+		require.NoError(t, err, "could not load trimmed cve database")
+		require.Equal(t, 2, database.Len())
+
+		matches, err := database.MatchString("cpe:2.3:a:jquery:jquery:3.4.1:*:*:*:*:*:*:*")
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.Equal(t, "CVE-2023-0004", matches[0].CVE.ID)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := LoadCVEDatabase(filepath.Join(t.TempDir(), "missing.json"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,236 @@
+package wappalyzer
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"strings"
+)
+
+// FaviconDatabase is a database of favicon hashes of technologies,
+// for identifying admin panels and appliances by their default favicon.
+type FaviconDatabase struct {
+	// mmh3 is organized as <mmh3 hash, entry>
+	mmh3 map[int32]FaviconEntry
+	// md5 is organized as <hex md5 hash, entry>
+	md5 map[string]FaviconEntry
+}
+
+// FaviconEntry is a known favicon of a technology.
+type FaviconEntry struct {
+	// Name is the technology name, as used by the fingerprints
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Confidence defaults to 100 if not set
+	Confidence int      `json:"confidence,omitempty"`
+	MMH3       []int32  `json:"mmh3,omitempty"`
+	MD5        []string `json:"md5,omitempty"`
+}
+
+// LoadFaviconDatabase loads a favicon database from a JSON file.
+func LoadFaviconDatabase(filePath string) (*FaviconDatabase, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewFaviconDatabase(f)
+}
+
+// NewFaviconDatabase creates a favicon database from a JSON list of entries.
+func NewFaviconDatabase(reader io.Reader) (*FaviconDatabase, error) {
+	var entries []FaviconEntry
+	if err := json.NewDecoder(reader).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	database := &FaviconDatabase{
+		mmh3: make(map[int32]FaviconEntry),
+		md5:  make(map[string]FaviconEntry),
+	}
+	for _, entry := range entries {
+		if err := database.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return database, nil
+}
+
+// Add adds a known favicon to the database.
+func (d *FaviconDatabase) Add(entry FaviconEntry) error {
+	if entry.Name == "" {
+		return fmt.Errorf("favicon entry without name")
+	}
+	if entry.Confidence == 0 {
+		entry.Confidence = 100
+	}
+	for _, hash := range entry.MMH3 {
+		d.mmh3[hash] = entry
+	}
+	for _, hash := range entry.MD5 {
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != md5.Size*2 {
+			return fmt.Errorf("invalid md5 hash for %s: %q", entry.Name, hash)
+		}
+		d.md5[strings.ToLower(hash)] = entry
+	}
+	return nil
+}
+
+// Lookup returns the technology with the favicon data.
+func (d *FaviconDatabase) Lookup(data []byte) (FaviconEntry, bool) {
+	if entry, ok := d.mmh3[FaviconHash(data)]; ok {
+		return entry, true
+	}
+	entry, ok := d.md5[FaviconMD5(data)]
+	return entry, ok
+}
+
+// FaviconHash returns the Shodan style mmh3 hash of a favicon, which is
+// the murmur3 hash of its base64 encoding with a newline every 76 characters.
+func FaviconHash(data []byte) int32 {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var builder strings.Builder
+	for len(encoded) > 76 {
+		builder.WriteString(encoded[:76])
+		builder.WriteByte('\n')
+		encoded = encoded[76:]
+	}
+	builder.WriteString(encoded)
+	builder.WriteByte('\n')
+
+	return int32(murmur3([]byte(builder.String()), 0))
+}
+
+// FaviconMD5 returns the hex md5 hash of a favicon.
+func FaviconMD5(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// murmur3 is the 32-bit x86 variant of murmur3.
+func murmur3(data []byte, seed uint32) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+
+	hash := seed
+	blocks := len(data) / 4
+	for i := 0; i < blocks; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+
+		hash ^= k
+		hash = bits.RotateLeft32(hash, 13)
+		hash = hash*5 + 0xe6546b64
+	}
+
+	var k uint32
+	tail := data[blocks*4:]
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		hash ^= k
+	}
+
+	hash ^= uint32(len(data))
+	hash ^= hash >> 16
+	hash *= 0x85ebca6b
+	hash ^= hash >> 13
+	hash *= 0xc2b2ae35
+	hash ^= hash >> 16
+	return hash
+}
+
+// SetFaviconDatabase sets the database of favicon hashes used
+// to identify technologies from their favicon.
+func (s *Wappalyze) SetFaviconDatabase(database *FaviconDatabase) {
+	s.favicons = database
+}
+
+// FingerprintWithFavicons identifies technologies on a target,
+// based on the received response headers and body.
+// It also returns the hrefs of the favicons linked from the page, which
+// are to be resolved against the page URL by the caller. Browsers fall
+// back to /favicon.ico when a page does not link any.
+//
+// Body should not be mutated while this function is being called, or it may
+// lead to unexpected things.
+func (s *Wappalyze) FingerprintWithFavicons(headers map[string][]string, body []byte) (map[string]struct{}, []string) {
+	uniqueFingerprints, info := s.fingerprint(headers, body)
+	return uniqueFingerprints.GetValues(), info.favicons
+}
+
+// FingerprintWithFaviconContents identifies technologies on a target,
+// based on the received response headers and body as well as the contents
+// of its favicons fetched by the caller, such as the ones linked from the
+// page as returned by FingerprintWithFavicons.
+//
+// Body should not be mutated while this function is being called, or it may
+// lead to unexpected things.
+func (s *Wappalyze) FingerprintWithFaviconContents(headers map[string][]string, body []byte, favicons [][]byte) map[string]struct{} {
+	uniqueFingerprints, _ := s.fingerprint(headers, body)
+
+	for _, app := range s.checkFavicons(favicons) {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// FingerprintFavicon identifies technologies from the contents of favicons
+// fetched by the caller, using the favicon database.
+func (s *Wappalyze) FingerprintFavicon(favicons ...[]byte) map[string]struct{} {
+	uniqueFingerprints := NewUniqueFingerprints()
+	for _, app := range s.checkFavicons(favicons) {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// checkFavicons checks the favicons against the favicon database
+func (s *Wappalyze) checkFavicons(favicons [][]byte) []matchPartResult {
+	if s.favicons == nil {
+		return nil
+	}
+
+	var technologies []matchPartResult
+	for _, favicon := range favicons {
+		entry, ok := s.favicons.Lookup(favicon)
+		if !ok {
+			continue
+		}
+		technologies = append(technologies, matchPartResult{
+			application: entry.Name,
+			version:     entry.Version,
+			confidence:  entry.Confidence,
+		})
+		if fingerprint, ok := s.fingerprints.Apps[entry.Name]; ok {
+			for _, implies := range fingerprint.implies {
+				technologies = append(technologies, matchPartResult{
+					application: implies,
+					confidence:  entry.Confidence,
+				})
+			}
+		}
+	}
+	return technologies
+}
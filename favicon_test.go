@@ -0,0 +1,76 @@
+package wappalyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaviconHash(t *testing.T) {
+	require.Equal(t, uint32(613153351), murmur3([]byte("hello"), 0), "could not get murmur3 hash")
+	require.Equal(t, uint32(0), murmur3(nil, 0), "could not get empty murmur3 hash")
+
+	// Expected values are from mmh3.hash(base64.encodebytes(data))
+	require.Equal(t, int32(1051234394), FaviconHash([]byte("favicon")), "could not get favicon hash")
+
+	var multiline []byte
+	for i := 0; i < 512; i++ {
+		multiline = append(multiline, byte(i))
+	}
+	require.Equal(t, int32(-1173581353), FaviconHash(multiline), "could not get multiline favicon hash")
+
+	require.Equal(t, "d41d8cd98f00b204e9800998ecf8427e", FaviconMD5(nil), "could not get favicon md5")
+}
+
+func TestFaviconDatabase(t *testing.T) {
+	panel := []byte("\x00\x00\x01\x00panel")
+	appliance := []byte("\x00\x00\x01\x00appliance")
+
+	database, err := NewFaviconDatabase(strings.NewReader(fmt.Sprintf(`[
+		{"name": "Jenkins", "mmh3": [%d]},
+		{"name": "Grafana", "version": "9.0", "confidence": 50, "md5": [%q]}
+	]`, FaviconHash(panel), strings.ToUpper(FaviconMD5(appliance)))))
+	require.NoError(t, err, "could not load favicon database")
+
+	entry, ok := database.Lookup(panel)
+	require.True(t, ok, "could not lookup mmh3 hash")
+	require.Equal(t, "Jenkins", entry.Name)
+	require.Equal(t, 100, entry.Confidence)
+
+	entry, ok = database.Lookup(appliance)
+	require.True(t, ok, "could not lookup md5 hash")
+	require.Equal(t, "Grafana", entry.Name)
+
+	_, ok = database.Lookup([]byte("unknown"))
+	require.False(t, ok, "could lookup unknown favicon")
+
+	_, err = NewFaviconDatabase(strings.NewReader(`[{"name": "Jenkins", "md5": ["zz"]}]`))
+	require.Error(t, err, "could load invalid md5 hash")
+
+	t.Run("fingerprint", func(t *testing.T) {
+		wappalyzer, err := New()
+		require.Nil(t, err, "could not create wappalyzer")
+		wappalyzer.SetFaviconDatabase(database)
+
+		_, favicons := wappalyzer.FingerprintWithFavicons(map[string][]string{}, []byte(`<html>
+<head>
+<link rel="Shortcut Icon" href="/static/favicon.ico">
+<link rel="stylesheet" href="/static/style.css">
+<link rel="icon" type="image/png" href="/static/icon.png" />
+<link rel="apple-touch-icon" href="/static/touch.png">
+</head>
+</html>`))
+		require.Equal(t, []string{"/static/favicon.ico", "/static/icon.png"}, favicons, "could not get favicons")
+
+		matches := wappalyzer.FingerprintFavicon([]byte("unknown"), panel, appliance)
+		require.Contains(t, matches, "Jenkins", "could not get mmh3 match")
+		require.Contains(t, matches, "Grafana:9.0", "could not get md5 match")
+		require.Contains(t, matches, "Go", "could not get implied technology")
+
+		matches = wappalyzer.FingerprintWithFaviconContents(map[string][]string{"Server": {"nginx/1.25.3"}}, []byte(`<html></html>`), [][]byte{panel})
+		require.Contains(t, matches, "Nginx:1.25.3", "could not get header match")
+		require.Contains(t, matches, "Jenkins", "could not get merged favicon match")
+	})
+}
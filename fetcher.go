@@ -0,0 +1,290 @@
+package wappalyzer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Fetcher retrieves the external scripts and stylesheets referenced by
+// a page, so that their contents can be matched as well.
+type Fetcher interface {
+	// Fetch returns the contents of the resource at targetURL,
+	// reading at most maxSize bytes of it.
+	Fetch(ctx context.Context, targetURL string, maxSize int64) ([]byte, error)
+}
+
+// FetchOptions contains the options for fetching external resources.
+// Zero values are replaced with the defaults.
+type FetchOptions struct {
+	// MaxSize is the maximum number of bytes read from a resource,
+	// larger resources are truncated.
+	MaxSize int64
+	// Concurrency is the maximum number of resources fetched at once.
+	Concurrency int
+	// CacheSize is the maximum number of resources kept in the cache.
+	CacheSize int
+	// AllowCrossOrigin allows fetching resources from other origins
+	// than the page, such as CDNs.
+	AllowCrossOrigin bool
+}
+
+// default options for fetching external resources
+const (
+	DefaultFetchMaxSize     = 2 * 1024 * 1024
+	DefaultFetchConcurrency = 4
+	DefaultFetchCacheSize   = 256
+)
+
+// fetcher is a fetcher configured with its options and cache
+type fetcher struct {
+	fetcher Fetcher
+	options FetchOptions
+	cache   *fetchCache
+}
+
+// SetFetcher sets the fetcher used to retrieve the external scripts and
+// stylesheets of pages passed to FingerprintWithFetcher, nil disables it.
+func (s *Wappalyze) SetFetcher(f Fetcher, options FetchOptions) {
+	if f == nil {
+		s.fetcher = nil
+		return
+	}
+	if options.MaxSize <= 0 {
+		options.MaxSize = DefaultFetchMaxSize
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultFetchConcurrency
+	}
+	if options.CacheSize <= 0 {
+		options.CacheSize = DefaultFetchCacheSize
+	}
+	s.fetcher = &fetcher{
+		fetcher: f,
+		options: options,
+		cache:   newFetchCache(options.CacheSize),
+	}
+}
+
+// FingerprintWithFetcher identifies technologies on a target,
+// based on its URL, the received response headers and body as well as
+// the contents of the external scripts and stylesheets it references,
+// which are retrieved with the fetcher.
+//
+// Scripts are matched against the scripts patterns and the hash database,
+// stylesheets against the css patterns and the hash database. The js
+// patterns require evaluating the scripts and are not matched. Resources
+// failing to be fetched are skipped.
+//
+// Body should not be mutated while this function is being called, or it may
+// lead to unexpected things.
+func (s *Wappalyze) FingerprintWithFetcher(ctx context.Context, targetURL string, headers map[string][]string, body []byte) map[string]struct{} {
+	uniqueFingerprints, info := s.fingerprint(headers, body)
+	addResults(uniqueFingerprints, s.checkURL(targetURL))
+
+	if s.fetcher == nil {
+		return uniqueFingerprints.GetValues()
+	}
+	base, err := url.Parse(targetURL)
+	if err != nil {
+		return uniqueFingerprints.GetValues()
+	}
+
+	scripts := s.fetcher.resolve(base, info.scripts)
+	stylesheets := s.fetcher.resolve(base, info.stylesheets)
+
+	results := make(chan []matchPartResult)
+	go func() {
+		defer close(results)
+
+		var wg sync.WaitGroup
+		semaphore := make(chan struct{}, s.fetcher.options.Concurrency)
+		fetch := func(resourceURL string, check func([]byte) []matchPartResult) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-semaphore }()
+
+			data, err := s.fetcher.fetch(ctx, resourceURL)
+			if err != nil {
+				return
+			}
+			results <- check(data)
+		}
+
+		for _, script := range scripts {
+			wg.Add(1)
+			go fetch(script, s.checkScriptContent)
+		}
+		for _, stylesheet := range stylesheets {
+			wg.Add(1)
+			go fetch(stylesheet, s.checkStylesheetContent)
+		}
+		wg.Wait()
+	}()
+
+	for technologies := range results {
+		addResults(uniqueFingerprints, technologies)
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// checkScriptContent checks the contents of an external script
+func (s *Wappalyze) checkScriptContent(data []byte) []matchPartResult {
+	technologies := s.checkContentHash(data)
+	if len(data) > 0 {
+		technologies = append(technologies, s.fingerprints.matchString(unsafeToString(data), scriptContentPart)...)
+	}
+	return technologies
+}
+
+// checkStylesheetContent checks the contents of an external stylesheet
+func (s *Wappalyze) checkStylesheetContent(data []byte) []matchPartResult {
+	return append(s.checkContentHash(data), s.checkCSS(data)...)
+}
+
+// resolve resolves the references of a page against its URL, keeping the
+// unique http(s) URLs allowed by the same-origin policy.
+func (f *fetcher) resolve(base *url.URL, references []string) []string {
+	var resolved []string
+	seen := make(map[string]struct{}, len(references))
+
+	for _, reference := range references {
+		parsed, err := base.Parse(strings.TrimSpace(reference))
+		if err != nil {
+			continue
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			continue
+		}
+		if !f.options.AllowCrossOrigin && !sameOrigin(base, parsed) {
+			continue
+		}
+		parsed.Fragment = ""
+
+		resourceURL := parsed.String()
+		if _, ok := seen[resourceURL]; ok {
+			continue
+		}
+		seen[resourceURL] = struct{}{}
+		resolved = append(resolved, resourceURL)
+	}
+	return resolved
+}
+
+// fetch fetches a resource through the cache, truncating it to the maximum size
+func (f *fetcher) fetch(ctx context.Context, resourceURL string) ([]byte, error) {
+	if data, ok := f.cache.get(resourceURL); ok {
+		return data, nil
+	}
+
+	data, err := f.fetcher.Fetch(ctx, resourceURL, f.options.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > f.options.MaxSize {
+		data = data[:f.options.MaxSize]
+	}
+	f.cache.set(resourceURL, data)
+	return data, nil
+}
+
+// sameOrigin reports whether two URLs have the same scheme, host and port
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) &&
+		strings.EqualFold(a.Hostname(), b.Hostname()) &&
+		urlPort(a) == urlPort(b)
+}
+
+// urlPort returns the port of a URL, or the default one of its scheme
+func urlPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
+	}
+	return ""
+}
+
+// fetchCache is a cache of fetched resources, evicting the oldest
+// resources once it's full.
+type fetchCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[string][]byte
+	order   []string
+}
+
+func newFetchCache(size int) *fetchCache {
+	return &fetchCache{
+		size:    size,
+		entries: make(map[string][]byte, size),
+	}
+}
+
+func (c *fetchCache) get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data, ok := c.entries[key]
+	return data, ok
+}
+
+func (c *fetchCache) set(key string, data []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	if len(c.order) >= c.size {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = data
+	c.order = append(c.order, key)
+}
+
+// HTTPFetcher is a Fetcher retrieving resources with an HTTP client.
+type HTTPFetcher struct {
+	client *http.Client
+}
+
+// NewHTTPFetcher creates a fetcher using client,
+// or http.DefaultClient if it's nil.
+func NewHTTPFetcher(client *http.Client) *HTTPFetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPFetcher{client: client}
+}
+
+// Fetch returns the contents of the resource at targetURL.
+func (f *HTTPFetcher) Fetch(ctx context.Context, targetURL string, maxSize int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d for %s", resp.StatusCode, targetURL)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSize))
+}
@@ -0,0 +1,106 @@
+package wappalyzer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprintWithFetcher(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"Acme Widgets": {"scripts": ["AcmeWidgets\\.init\\(\\{version:'([\\d.]+)'\\;version:\\1"]},
+		"Tailwind CSS": {"css": ["--tw-(?:rotate|translate)"]},
+		"Remote Lib": {"scripts": ["RemoteLib"]}
+	}`)
+
+	var count atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/static/app.js", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "AcmeWidgets.init({version:'1.4.2'});")
+	})
+	mux.HandleFunc("/static/app.css", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, ".rotate{--tw-rotate:45deg}")
+	})
+	mux.HandleFunc("/static/large.js", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, strings.Repeat(" ", 64)+"RemoteLib")
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		mux.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "RemoteLib")
+	}))
+	defer remote.Close()
+
+	body := []byte(fmt.Sprintf(`<html>
+<head>
+<link rel="stylesheet" href="/static/app.css">
+<script src="static/app.js"></script>
+<script src="/static/app.js#duplicate"></script>
+<script src="%s/lib.js"></script>
+<script src="/static/missing.js"></script>
+</head>
+</html>`, remote.URL))
+
+	t.Run("without fetcher", func(t *testing.T) {
+		matches := wappalyzer.FingerprintWithFetcher(context.Background(), server.URL+"/", map[string][]string{}, body)
+		require.Empty(t, matches, "could get matches without fetcher")
+	})
+
+	wappalyzer.SetFetcher(NewHTTPFetcher(server.Client()), FetchOptions{})
+
+	matches := wappalyzer.FingerprintWithFetcher(context.Background(), server.URL+"/", map[string][]string{}, body)
+	require.Equal(t, map[string]struct{}{"Acme Widgets:1.4.2": {}, "Tailwind CSS": {}}, matches, "could not get fetched matches")
+	require.Equal(t, int32(3), count.Load(), "could not fetch same-origin resources once")
+
+	t.Run("cache", func(t *testing.T) {
+		matches := wappalyzer.FingerprintWithFetcher(context.Background(), server.URL+"/", map[string][]string{}, body)
+		require.Len(t, matches, 2, "could not get cached matches")
+		require.Equal(t, int32(4), count.Load(), "could fetch cached resources again")
+	})
+
+	t.Run("cross origin", func(t *testing.T) {
+		wappalyzer.SetFetcher(NewHTTPFetcher(nil), FetchOptions{AllowCrossOrigin: true})
+
+		matches := wappalyzer.FingerprintWithFetcher(context.Background(), server.URL+"/", map[string][]string{}, body)
+		require.Contains(t, matches, "Remote Lib", "could not get cross-origin match")
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		wappalyzer.SetFetcher(NewHTTPFetcher(nil), FetchOptions{MaxSize: 32})
+
+		matches := wappalyzer.FingerprintWithFetcher(context.Background(), server.URL+"/", map[string][]string{}, []byte(`<script src="/static/large.js"></script>`))
+		require.Empty(t, matches, "could match truncated contents")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		wappalyzer.SetFetcher(NewHTTPFetcher(nil), FetchOptions{Concurrency: 1})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		matches := wappalyzer.FingerprintWithFetcher(ctx, server.URL+"/", map[string][]string{}, body)
+		require.Empty(t, matches, "could get matches with canceled context")
+	})
+}
+
+func TestFetchCache(t *testing.T) {
+	cache := newFetchCache(2)
+	cache.set("a", []byte("1"))
+	cache.set("b", []byte("2"))
+	cache.set("c", []byte("3"))
+
+	_, ok := cache.get("a")
+	require.False(t, ok, "could get evicted entry")
+	data, ok := cache.get("c")
+	require.True(t, ok, "could not get cached entry")
+	require.Equal(t, []byte("3"), data)
+}
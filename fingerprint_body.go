@@ -2,14 +2,27 @@ package wappalyzer
 
 import (
 	"bytes"
+	"slices"
+	"strings"
 	"unsafe"
 
 	"golang.org/x/net/html"
 )
 
+// bodyInfo contains information collected while tokenizing the HTML body
+type bodyInfo struct {
+	// favicons contains the hrefs of the icon link tags
+	favicons []string
+	// scripts contains the srcs of the script tags
+	scripts []string
+	// stylesheets contains the hrefs of the stylesheet link tags
+	stylesheets []string
+}
+
 // checkBody checks for fingerprints in the HTML body
-func (s *Wappalyze) checkBody(body []byte) []matchPartResult {
+func (s *Wappalyze) checkBody(body []byte) ([]matchPartResult, bodyInfo) {
 	var technologies []matchPartResult
+	var info bodyInfo
 
 	bodyString := unsafeToString(body)
 
@@ -21,13 +34,50 @@ func (s *Wappalyze) checkBody(body []byte) []matchPartResult {
 	// Tokenize the HTML document and check for fingerprints as required
 	tokenizer := html.NewTokenizer(bytes.NewReader(body))
 
+	// text contains the visible text of the page, and rawTextTag the
+	// element whose raw text is being tokenized if it's not visible.
+	text := &visibleText{}
+	var rawTextTag string
+	// css contains the style blocks and style attributes of the page
+	css := &strings.Builder{}
+
 	for {
 		tt := tokenizer.Next()
 		switch tt {
 		case html.ErrorToken:
-			return technologies
+			if text.builder.Len() > 0 {
+				technologies = append(
+					technologies,
+					s.fingerprints.matchString(text.builder.String(), textPart)...,
+				)
+			}
+			if css.Len() > 0 {
+				technologies = append(
+					technologies,
+					s.fingerprints.matchString(css.String(), cssPart)...,
+				)
+			}
+			return technologies, info
+		case html.TextToken:
+			switch rawTextTag {
+			case "":
+				if s.hasText {
+					text.write(tokenizer.Text())
+				}
+			case "style":
+				appendStyle(css, unsafeToString(tokenizer.Raw()))
+			}
+		case html.EndTagToken:
+			rawTextTag = ""
+			name, _ := tokenizer.TagName()
+			text.separate(string(name))
 		case html.StartTagToken:
 			token := tokenizer.Token()
+			if _, ok := invisibleTextTags[token.Data]; ok {
+				rawTextTag = token.Data
+			}
+			text.separate(token.Data)
+			appendStyle(css, getAttribute(token, "style"))
 			switch token.Data {
 			case "script":
 				// Check if the script tag has a source file to check
@@ -38,14 +88,18 @@ func (s *Wappalyze) checkBody(body []byte) []matchPartResult {
 						technologies,
 						s.fingerprints.matchString(source, scriptPart)...,
 					)
+					technologies = append(
+						technologies,
+						s.checkIntegrity(getAttribute(token, "integrity"))...,
+					)
+					if source != "" {
+						info.scripts = append(info.scripts, source)
+					}
 					continue
 				}
 
-				// Check the text attribute of the tag for javascript based technologies.
-				// The next token should be the contents of the script tag
-				if tokenType := tokenizer.Next(); tokenType != html.TextToken {
-					continue
-				}
+				// The contents of inline scripts are the next text token, which
+				// is left to the loop so that the end tag is still tokenized.
 
 				// TODO: JS requires a running VM, for checking properties. Only
 				// possible with headless for now :(
@@ -56,32 +110,105 @@ func (s *Wappalyze) checkBody(body []byte) []matchPartResult {
 				// 	s.fingerprints.matchString(data, jsPart)...,
 				// )
 			case "meta":
-				// For meta tag, we are only interested in name and content attributes.
-				name, content, found := getMetaNameAndContent(token)
-				if !found {
-					continue
-				}
+				// For meta tag, we are only interested in the key and content attributes.
+				technologies = append(technologies, s.checkMeta(token)...)
+			case "link":
+				// Stylesheets and preloaded scripts can be identified by their integrity
 				technologies = append(
 					technologies,
-					s.fingerprints.matchKeyValueString(name, content, metaPart)...,
+					s.checkIntegrity(getAttribute(token, "integrity"))...,
 				)
+				info.collectLink(token)
 			}
 		case html.SelfClosingTagToken:
 			token := tokenizer.Token()
-			if token.Data != "meta" {
-				continue
+			text.separate(token.Data)
+			appendStyle(css, getAttribute(token, "style"))
+			switch token.Data {
+			case "meta":
+				// Parse the meta tag and check for tech
+				technologies = append(technologies, s.checkMeta(token)...)
+			case "link":
+				technologies = append(
+					technologies,
+					s.checkIntegrity(getAttribute(token, "integrity"))...,
+				)
+				info.collectLink(token)
 			}
+		}
+	}
+}
 
-			// Parse the meta tag and check for tech
-			name, content, found := getMetaNameAndContent(token)
-			if !found {
-				continue
-			}
-			technologies = append(
-				technologies,
-				s.fingerprints.matchKeyValueString(name, content, metaPart)...,
-			)
+// invisibleTextTags are the elements with raw text contents
+// that are not part of the visible text of a page.
+var invisibleTextTags = map[string]struct{}{
+	"script":   {},
+	"style":    {},
+	"title":    {},
+	"noscript": {},
+	"iframe":   {},
+	"noembed":  {},
+	"noframes": {},
+	"xmp":      {},
+}
+
+// appendStyle appends a style block or attribute to the page css
+func appendStyle(css *strings.Builder, style string) {
+	if style == "" {
+		return
+	}
+	if css.Len() > 0 {
+		css.WriteByte('\n')
+	}
+	css.WriteString(style)
+}
+
+// inlineTextTags are the elements which don't separate
+// the visible text around them.
+var inlineTextTags = map[string]struct{}{
+	"a":      {},
+	"abbr":   {},
+	"b":      {},
+	"code":   {},
+	"em":     {},
+	"font":   {},
+	"i":      {},
+	"small":  {},
+	"span":   {},
+	"strong": {},
+	"sub":    {},
+	"sup":    {},
+	"u":      {},
+}
+
+// visibleText builds the visible text of a page from its text tokens,
+// collapsing whitespace as browsers do when rendering.
+type visibleText struct {
+	builder strings.Builder
+	// space indicates that a space is to be written before the next text
+	space bool
+}
+
+// write appends the unescaped contents of a text token
+func (t *visibleText) write(data []byte) {
+	for _, c := range data {
+		switch c {
+		case ' ', '\t', '\n', '\r', '\f':
+			t.space = true
+			continue
+		}
+		if t.space && t.builder.Len() > 0 {
+			t.builder.WriteByte(' ')
 		}
+		t.space = false
+		t.builder.WriteByte(c)
+	}
+}
+
+// separate separates the text around a tag unless it's an inline one
+func (t *visibleText) separate(tag string) {
+	if _, ok := inlineTextTags[tag]; !ok {
+		t.space = true
 	}
 }
 
@@ -110,22 +237,41 @@ func (s *Wappalyze) getTitle(body []byte) string {
 	}
 }
 
-// getMetaNameAndContent gets name and content attributes from meta html token
-func getMetaNameAndContent(token html.Token) (string, string, bool) {
-	if len(token.Attr) < keyValuePairLength {
-		return "", "", false
+// checkMeta checks a meta tag for fingerprints, under each of its keys
+func (s *Wappalyze) checkMeta(token html.Token) []matchPartResult {
+	keys, content, found := getMetaKeysAndContent(token)
+	if !found {
+		return nil
 	}
 
-	var name, content string
+	var technologies []matchPartResult
+	for _, key := range keys {
+		technologies = append(
+			technologies,
+			s.fingerprints.matchKeyValueString(key, content, metaPart)...,
+		)
+	}
+	return technologies
+}
+
+// getMetaKeysAndContent gets the lowercased name, property and http-equiv
+// attributes along with the content attribute from a meta html token
+func getMetaKeysAndContent(token html.Token) ([]string, string, bool) {
+	var keys []string
+	var content string
 	for _, attr := range token.Attr {
 		switch attr.Key {
-		case "name":
-			name = attr.Val
+		case "name", "property", "http-equiv":
+			key := strings.ToLower(strings.TrimSpace(attr.Val))
+			if key == "" || slices.Contains(keys, key) {
+				continue
+			}
+			keys = append(keys, key)
 		case "content":
 			content = attr.Val
 		}
 	}
-	return name, content, true
+	return keys, content, len(keys) > 0
 }
 
 // getScriptSource gets src tag from a script tag
@@ -144,6 +290,34 @@ func getScriptSource(token html.Token) (string, bool) {
 	return source, true
 }
 
+// collectLink collects the information needed from a link tag
+func (info *bodyInfo) collectLink(token html.Token) {
+	href := getAttribute(token, "href")
+	if href == "" {
+		return
+	}
+	for _, rel := range strings.Fields(strings.ToLower(getAttribute(token, "rel"))) {
+		switch rel {
+		case "icon":
+			info.favicons = append(info.favicons, href)
+			return
+		case "stylesheet":
+			info.stylesheets = append(info.stylesheets, href)
+			return
+		}
+	}
+}
+
+// getAttribute gets the value of an attribute from a html token
+func getAttribute(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
 // unsafeToString converts a byte slice to string and does it with
 // zero allocations.
 //
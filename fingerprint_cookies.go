@@ -1,6 +1,8 @@
 package wappalyzer
 
 import (
+	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -14,42 +16,163 @@ func (s *Wappalyze) checkCookies(cookies []string) []matchPartResult {
 	return technologies
 }
 
+// checkRequestCookies checks if the cookies sent to a target match
+// the fingerprints and returns the matched IDs if any.
+func (s *Wappalyze) checkRequestCookies(cookies []*http.Cookie) []matchPartResult {
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	normalized := make(map[string]string, len(cookies))
+	for _, cookie := range cookies {
+		if cookie == nil || cookie.Name == "" {
+			continue
+		}
+		normalized[strings.ToLower(cookie.Name)] = cookie.Value
+	}
+	return s.fingerprints.matchMapString(normalized, cookiesPart)
+}
+
+// FingerprintCookies identifies technologies from the cookies held
+// by a client for a target, as sent in requests to it.
+func (s *Wappalyze) FingerprintCookies(cookies []*http.Cookie) map[string]struct{} {
+	uniqueFingerprints := NewUniqueFingerprints()
+
+	for _, app := range s.checkRequestCookies(cookies) {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// FingerprintCookieHeader is like FingerprintCookies but accepts
+// the value of a Cookie request header.
+func (s *Wappalyze) FingerprintCookieHeader(header string) map[string]struct{} {
+	return s.FingerprintCookies(parseCookieHeader(header))
+}
+
+// FingerprintCookieJar is like FingerprintCookies but accepts a
+// cookie jar along with the URL to get the cookies for.
+func (s *Wappalyze) FingerprintCookieJar(jar http.CookieJar, targetURL *url.URL) map[string]struct{} {
+	if jar == nil || targetURL == nil {
+		return NewUniqueFingerprints().GetValues()
+	}
+	return s.FingerprintCookies(jar.Cookies(targetURL))
+}
+
+// FingerprintWithCookies identifies technologies on a target,
+// based on the received response headers and body as well as the
+// cookies sent with the request, which are often the only ones left
+// once a session is established.
+//
+// Body should not be mutated while this function is being called, or it may
+// lead to unexpected things.
+func (s *Wappalyze) FingerprintWithCookies(headers map[string][]string, body []byte, cookies []*http.Cookie) map[string]struct{} {
+	uniqueFingerprints, _ := s.fingerprint(headers, body)
+
+	for _, app := range s.checkRequestCookies(cookies) {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// parseCookieHeader parses the cookies of a Cookie request header.
+//
+// Unlike http.ParseCookie, invalid pairs are skipped instead of
+// failing the whole header.
+func parseCookieHeader(header string) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, pair := range strings.Split(header, ";") {
+		name, value, ok := parseSetCookie(pair)
+		if !ok {
+			continue
+		}
+		cookies = append(cookies, &http.Cookie{Name: name, Value: value})
+	}
+	return cookies
+}
+
 const keyValuePairLength = 2
 
-// normalizeCookies normalizes the cookies and returns an
-// easily parsed format that can be processed upon.
+// normalizeCookies parses the Set-Cookie header values and returns
+// the cookies as <lowercased name, value>.
 func (s *Wappalyze) normalizeCookies(cookies []string) map[string]string {
 	normalized := make(map[string]string)
 
-	for _, part := range cookies {
-		parts := strings.SplitN(strings.Trim(part, " "), "=", keyValuePairLength)
-		if len(parts) < keyValuePairLength {
+	for _, cookie := range cookies {
+		name, value, ok := parseSetCookie(cookie)
+		if !ok {
 			continue
 		}
-		normalized[parts[0]] = parts[1]
+		normalized[strings.ToLower(name)] = value
 	}
 	return normalized
 }
 
-// findSetCookie finds the set cookie header from the normalized headers
-func (s *Wappalyze) findSetCookie(headers map[string]string) []string {
-	value, ok := headers["set-cookie"]
-	if !ok {
-		return nil
+// parseSetCookie parses the name and value of a Set-Cookie header value,
+// ignoring its attributes, as described in RFC 6265 section 5.2.
+func parseSetCookie(cookie string) (string, string, bool) {
+	pair, _, _ := strings.Cut(cookie, ";")
+
+	parts := strings.SplitN(pair, "=", keyValuePairLength)
+	if len(parts) < keyValuePairLength {
+		return "", "", false
+	}
+	name := strings.TrimSpace(parts[0])
+	if name == "" {
+		return "", "", false
 	}
+	return name, strings.TrimSpace(parts[1]), true
+}
 
+// findSetCookie finds the raw Set-Cookie header values from the headers.
+//
+// Each value is a single cookie, although values folded into one line
+// with commas, as done by some clients, are split back into cookies.
+func (s *Wappalyze) findSetCookie(headers map[string][]string) []string {
 	var values []string
-	for _, v := range strings.Split(value, " ") {
-		if v == "" {
+	for header, headerValues := range headers {
+		if !strings.EqualFold(header, "set-cookie") {
 			continue
 		}
-		if strings.Contains(v, ",") {
-			values = append(values, strings.Split(v, ",")...)
-		} else if strings.Contains(v, ";") {
-			values = append(values, strings.Split(v, ";")...)
-		} else {
-			values = append(values, v)
+		for _, value := range headerValues {
+			values = append(values, splitSetCookie(value)...)
 		}
 	}
 	return values
 }
+
+// splitSetCookie splits Set-Cookie header values folded with commas.
+//
+// A comma only starts a new cookie if it's followed by a name-value pair,
+// so that commas in dates of expires attributes are kept.
+func splitSetCookie(value string) []string {
+	var cookies []string
+
+	start := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] != ',' || !isCookiePairStart(value[i+1:]) {
+			continue
+		}
+		if cookie := strings.TrimSpace(value[start:i]); cookie != "" {
+			cookies = append(cookies, cookie)
+		}
+		start = i + 1
+	}
+	if cookie := strings.TrimSpace(value[start:]); cookie != "" {
+		cookies = append(cookies, cookie)
+	}
+	return cookies
+}
+
+// isCookiePairStart reports whether data starts with a cookie name-value pair
+func isCookiePairStart(data string) bool {
+	data = strings.TrimLeft(data, " \t")
+
+	end := strings.IndexAny(data, ";,")
+	if end == -1 {
+		end = len(data)
+	}
+	name, _, ok := strings.Cut(data[:end], "=")
+	name = strings.TrimRight(name, " \t")
+	return ok && name != "" && !strings.ContainsAny(name, " \t")
+}
@@ -0,0 +1,23 @@
+package wappalyzer
+
+// FingerprintCSS identifies technologies from the contents of
+// stylesheets fetched by the caller, using the css patterns.
+func (s *Wappalyze) FingerprintCSS(stylesheets ...[]byte) map[string]struct{} {
+	uniqueFingerprints := NewUniqueFingerprints()
+
+	for _, stylesheet := range stylesheets {
+		for _, app := range s.checkCSS(stylesheet) {
+			uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+		}
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// checkCSS checks if a stylesheet matches the fingerprints
+// and returns the matched IDs if any.
+func (s *Wappalyze) checkCSS(stylesheet []byte) []matchPartResult {
+	if len(stylesheet) == 0 {
+		return nil
+	}
+	return s.fingerprints.matchString(unsafeToString(stylesheet), cssPart)
+}
@@ -0,0 +1,34 @@
+package wappalyzer
+
+import (
+	"strings"
+)
+
+// FingerprintDNS identifies technologies on a target based on its DNS
+// records, such as TXT, MX, NS, CNAME and SOA records.
+//
+// Records are organized as <record type, values>, with values in their
+// presentation format, e.g. {"MX": {"1 aspmx.l.google.com."}}. No
+// resolving is done, the records are to be collected by the caller.
+func (s *Wappalyze) FingerprintDNS(records map[string][]string) map[string]struct{} {
+	uniqueFingerprints := NewUniqueFingerprints()
+
+	for _, app := range s.checkDNS(records) {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// checkDNS checks if the DNS records of a target match the fingerprints
+// and returns the matched IDs if any.
+func (s *Wappalyze) checkDNS(records map[string][]string) []matchPartResult {
+	var technologies []matchPartResult
+
+	for record, values := range records {
+		record = strings.ToUpper(record)
+		for _, value := range values {
+			technologies = append(technologies, s.fingerprints.matchKeyValueString(record, value, dnsPart)...)
+		}
+	}
+	return technologies
+}
@@ -17,7 +17,7 @@ func (s *Wappalyze) normalizeHeaders(headers map[string][]string) map[string]str
 	data := getHeadersMap(headers)
 
 	for header, value := range data {
-		normalized[strings.ToLower(header)] = strings.ToLower(value)
+		normalized[strings.ToLower(header)] = value
 	}
 	return normalized
 }
@@ -0,0 +1,61 @@
+package wappalyzer
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// FingerprintRobots identifies technologies on a target based on
+// the contents of its robots.txt file.
+func (s *Wappalyze) FingerprintRobots(body []byte) map[string]struct{} {
+	uniqueFingerprints := NewUniqueFingerprints()
+
+	for _, app := range s.checkRobots(body) {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// checkRobots checks if the robots.txt file of a target matches
+// the fingerprints and returns the matched IDs if any.
+func (s *Wappalyze) checkRobots(body []byte) []matchPartResult {
+	robots := normalizeRobots(body)
+	if robots == "" {
+		return nil
+	}
+	return s.fingerprints.matchString(robots, robotsPart)
+}
+
+// normalizeRobots parses the directives of a robots.txt file and returns
+// them one per line as "Field: value", without comments and blank lines,
+// so that patterns don't depend on the spacing used by the file.
+func normalizeRobots(body []byte) string {
+	builder := &strings.Builder{}
+	body = bytes.TrimPrefix(body, []byte("\ufeff"))
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 4096), len(body)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.IndexByte(line, '#'); index != -1 {
+			line = line[:index]
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field = strings.TrimSpace(field)
+		if field == "" || strings.ContainsAny(field, " \t") {
+			continue
+		}
+
+		if builder.Len() > 0 {
+			builder.WriteByte('\n')
+		}
+		builder.WriteString(field)
+		builder.WriteString(": ")
+		builder.WriteString(strings.TrimSpace(value))
+	}
+	return builder.String()
+}
@@ -0,0 +1,80 @@
+package wappalyzer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+)
+
+// FingerprintCertificates identifies technologies on a target based on
+// its TLS certificate chain, as in tls.ConnectionState.PeerCertificates.
+//
+// Rules are matched against the leaf certificate, which is the first of
+// the chain: certIssuer against the organizations and common name of its
+// issuer, certSubject against those of its subject and certSAN against
+// its DNS names.
+func (s *Wappalyze) FingerprintCertificates(certificates []*x509.Certificate) map[string]struct{} {
+	uniqueFingerprints := NewUniqueFingerprints()
+
+	for _, app := range s.checkCertificates(certificates) {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// FingerprintTLS is like FingerprintCertificates but accepts the state
+// of a TLS connection, such as http.Response.TLS.
+func (s *Wappalyze) FingerprintTLS(state *tls.ConnectionState) map[string]struct{} {
+	if state == nil {
+		return NewUniqueFingerprints().GetValues()
+	}
+	return s.FingerprintCertificates(state.PeerCertificates)
+}
+
+// FingerprintWithTLS identifies technologies on a target,
+// based on the received response headers, body and TLS connection state,
+// which can be nil for plain HTTP responses.
+//
+// Body should not be mutated while this function is being called, or it may
+// lead to unexpected things.
+func (s *Wappalyze) FingerprintWithTLS(headers map[string][]string, body []byte, state *tls.ConnectionState) map[string]struct{} {
+	uniqueFingerprints, _ := s.fingerprint(headers, body)
+
+	if state != nil {
+		for _, app := range s.checkCertificates(state.PeerCertificates) {
+			uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+		}
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// checkCertificates checks if the leaf certificate of a chain matches
+// the fingerprints and returns the matched IDs if any.
+func (s *Wappalyze) checkCertificates(certificates []*x509.Certificate) []matchPartResult {
+	if len(certificates) == 0 || certificates[0] == nil {
+		return nil
+	}
+	leaf := certificates[0]
+
+	var technologies []matchPartResult
+	for _, name := range certificateNames(leaf.Issuer) {
+		technologies = append(technologies, s.fingerprints.matchString(name, certIssuerPart)...)
+	}
+	for _, name := range certificateNames(leaf.Subject) {
+		technologies = append(technologies, s.fingerprints.matchString(name, certSubjectPart)...)
+	}
+	for _, name := range leaf.DNSNames {
+		technologies = append(technologies, s.fingerprints.matchString(name, certSANPart)...)
+	}
+	return technologies
+}
+
+// certificateNames returns the organizations and common name of a certificate name
+func certificateNames(name pkix.Name) []string {
+	names := make([]string, 0, len(name.Organization)+1)
+	names = append(names, name.Organization...)
+	if name.CommonName != "" {
+		names = append(names, name.CommonName)
+	}
+	return names
+}
@@ -0,0 +1,74 @@
+package wappalyzer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSDetect(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"Cloudflare": {"certIssuer": ["Cloudflare"]},
+		"Let's Encrypt": {"certIssuer": ["Let's Encrypt"]},
+		"Heroku": {"certSAN": ["\\.herokuapp\\.com$"], "implies": ["Ruby"]},
+		"Fortinet": {"certSubject": ["^FortiGate"]}
+	}`)
+
+	ca := newTestCertificate(t, nil, pkix.Name{Organization: []string{"Let's Encrypt"}, CommonName: "R3"})
+	leaf := newTestCertificate(t, ca, pkix.Name{CommonName: "app.herokuapp.com"}, "app.herokuapp.com")
+
+	matches := wappalyzer.FingerprintCertificates([]*x509.Certificate{leaf.Leaf, ca.Leaf})
+	require.Equal(t, map[string]struct{}{"Let's Encrypt": {}, "Heroku": {}, "Ruby": {}}, matches, "could not get certificate matches")
+
+	t.Run("connection state", func(t *testing.T) {
+		appliance := newTestCertificate(t, nil, pkix.Name{CommonName: "FortiGate-100F"})
+
+		matches := wappalyzer.FingerprintWithTLS(map[string][]string{}, []byte(""), &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{appliance.Leaf},
+		})
+		require.Equal(t, map[string]struct{}{"Fortinet": {}}, matches, "could not get connection state matches")
+
+		require.Empty(t, wappalyzer.FingerprintTLS(nil), "could get matches without connection state")
+		require.Empty(t, wappalyzer.FingerprintWithTLS(map[string][]string{}, []byte(""), nil), "could get matches without connection state")
+	})
+}
+
+// newTestCertificate creates a certificate signed by parent,
+// or a self-signed one if parent is nil.
+func newTestCertificate(t *testing.T, parent *tls.Certificate, subject pkix.Name, dnsNames ...string) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "could not generate key")
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               subject,
+		DNSNames:              dnsNames,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+	}
+	issuer, signer := template, interface{}(key)
+	if parent != nil {
+		issuer, signer = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	require.NoError(t, err, "could not create certificate")
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err, "could not parse certificate")
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        certificate,
+	}
+}
@@ -0,0 +1,27 @@
+package wappalyzer
+
+// checkURL checks if the URL of a target matches the fingerprints
+// and returns the matched IDs if any.
+func (s *Wappalyze) checkURL(targetURL string) []matchPartResult {
+	if targetURL == "" {
+		return nil
+	}
+	return s.fingerprints.matchString(targetURL, urlPart)
+}
+
+// FingerprintWithURL identifies technologies on a target,
+// based on its URL as well as the received response headers and body.
+//
+// The URL should be the final URL of the page, after redirects, including
+// the scheme, as url patterns match against the whole URL.
+//
+// Body should not be mutated while this function is being called, or it may
+// lead to unexpected things.
+func (s *Wappalyze) FingerprintWithURL(targetURL string, headers map[string][]string, body []byte) map[string]struct{} {
+	uniqueFingerprints, _ := s.fingerprint(headers, body)
+
+	for _, app := range s.checkURL(targetURL) {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+	return uniqueFingerprints.GetValues()
+}
@@ -2,6 +2,7 @@ package wappalyzer
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Fingerprints contains a map of fingerprints for tech detection
@@ -21,7 +22,15 @@ type Fingerprint struct {
 	HTML        []string                          `json:"html"`
 	Script      []string                          `json:"scripts"`
 	ScriptSrc   []string                          `json:"scriptSrc"`
+	URL         []string                          `json:"url"`
 	Meta        map[string][]string               `json:"meta"`
+	DNS         map[string][]string               `json:"dns"`
+	CertIssuer  []string                          `json:"certIssuer"`
+	CertSubject []string                          `json:"certSubject"`
+	CertSAN     []string                          `json:"certSAN"`
+	Robots      []string                          `json:"robots"`
+	Text        []string                          `json:"text"`
+	Probe       map[string]string                 `json:"probe"`
 	Implies     []string                          `json:"implies"`
 	Description string                            `json:"description"`
 	Website     string                            `json:"website"`
@@ -35,6 +44,16 @@ type CompiledFingerprints struct {
 	Apps map[string]*CompiledFingerprint
 }
 
+// hasText returns true if any fingerprint has text patterns
+func (f *CompiledFingerprints) hasText() bool {
+	for _, fingerprint := range f.Apps {
+		if len(fingerprint.text) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // CompiledFingerprint contains the compiled fingerprints from the tech json
 type CompiledFingerprint struct {
 	// cats contain categories that are implicit with this tech
@@ -47,22 +66,46 @@ type CompiledFingerprint struct {
 	website string
 	// icon contains a Icon associated with the fingerprint
 	icon string
+	// css contains fingerprints for stylesheets
+	css []*ParsedPattern
 	// cookies contains fingerprints for target cookies
 	cookies map[string]*ParsedPattern
+	// cookieKeys contains fingerprints for cookies with wildcard or regex names
+	cookieKeys []keyPattern
 	// js contains fingerprints for the js file
 	js map[string]*ParsedPattern
 	// dom contains fingerprints for the target dom
 	dom map[string]map[string]*ParsedPattern
 	// headers contains fingerprints for target headers
 	headers map[string]*ParsedPattern
+	// headerKeys contains fingerprints for headers with wildcard or regex names
+	headerKeys []keyPattern
 	// html contains fingerprints for the target HTML
 	html []*ParsedPattern
 	// script contains fingerprints for scripts
 	script []*ParsedPattern
 	// scriptSrc contains fingerprints for script srcs
 	scriptSrc []*ParsedPattern
+	// url contains fingerprints for the target URL
+	url []*ParsedPattern
 	// meta contains fingerprints for meta tags
 	meta map[string][]*ParsedPattern
+	// metaPresence contains the meta tags that only have to be present
+	metaPresence map[string]struct{}
+	// dns contains fingerprints for DNS records by record type
+	dns map[string][]*ParsedPattern
+	// certIssuer contains fingerprints for the TLS certificate issuer
+	certIssuer []*ParsedPattern
+	// certSubject contains fingerprints for the TLS certificate subject
+	certSubject []*ParsedPattern
+	// certSAN contains fingerprints for the TLS certificate DNS names
+	certSAN []*ParsedPattern
+	// robots contains fingerprints for the robots.txt file
+	robots []*ParsedPattern
+	// text contains fingerprints for the visible text of the page
+	text []*ParsedPattern
+	// probe contains fingerprints for the responses of probed paths
+	probe map[string]*ParsedPattern
 	// cpe contains the cpe for a fingerpritn
 	cpe string
 }
@@ -100,25 +143,44 @@ const (
 	htmlPart
 	scriptPart
 	metaPart
+	urlPart
+	dnsPart
+	certIssuerPart
+	certSubjectPart
+	certSANPart
+	robotsPart
+	textPart
+	cssPart
+	scriptContentPart
 )
 
 // loadPatterns loads the fingerprint patterns and compiles regexes
 func compileFingerprint(fingerprint *Fingerprint) *CompiledFingerprint {
 	compiled := &CompiledFingerprint{
-		cats:        fingerprint.Cats,
-		implies:     fingerprint.Implies,
-		description: fingerprint.Description,
-		website:     fingerprint.Website,
-		icon:        fingerprint.Icon,
-		dom:         make(map[string]map[string]*ParsedPattern),
-		cookies:     make(map[string]*ParsedPattern),
-		js:          make(map[string]*ParsedPattern),
-		headers:     make(map[string]*ParsedPattern),
-		html:        make([]*ParsedPattern, 0, len(fingerprint.HTML)),
-		script:      make([]*ParsedPattern, 0, len(fingerprint.Script)),
-		scriptSrc:   make([]*ParsedPattern, 0, len(fingerprint.ScriptSrc)),
-		meta:        make(map[string][]*ParsedPattern),
-		cpe:         fingerprint.CPE,
+		cats:         fingerprint.Cats,
+		implies:      fingerprint.Implies,
+		description:  fingerprint.Description,
+		website:      fingerprint.Website,
+		icon:         fingerprint.Icon,
+		dom:          make(map[string]map[string]*ParsedPattern),
+		css:          make([]*ParsedPattern, 0, len(fingerprint.CSS)),
+		cookies:      make(map[string]*ParsedPattern),
+		js:           make(map[string]*ParsedPattern),
+		headers:      make(map[string]*ParsedPattern),
+		html:         make([]*ParsedPattern, 0, len(fingerprint.HTML)),
+		script:       make([]*ParsedPattern, 0, len(fingerprint.Script)),
+		scriptSrc:    make([]*ParsedPattern, 0, len(fingerprint.ScriptSrc)),
+		url:          make([]*ParsedPattern, 0, len(fingerprint.URL)),
+		meta:         make(map[string][]*ParsedPattern),
+		metaPresence: make(map[string]struct{}),
+		dns:          make(map[string][]*ParsedPattern),
+		certIssuer:   make([]*ParsedPattern, 0, len(fingerprint.CertIssuer)),
+		certSubject:  make([]*ParsedPattern, 0, len(fingerprint.CertSubject)),
+		certSAN:      make([]*ParsedPattern, 0, len(fingerprint.CertSAN)),
+		robots:       make([]*ParsedPattern, 0, len(fingerprint.Robots)),
+		text:         make([]*ParsedPattern, 0, len(fingerprint.Text)),
+		probe:        make(map[string]*ParsedPattern),
+		cpe:          fingerprint.CPE,
 	}
 
 	for dom, patterns := range fingerprint.Dom {
@@ -149,11 +211,27 @@ func compileFingerprint(fingerprint *Fingerprint) *CompiledFingerprint {
 		}
 	}
 
+	for _, pattern := range fingerprint.CSS {
+		fingerprint, err := ParsePattern(pattern)
+		if err != nil {
+			continue
+		}
+		compiled.css = append(compiled.css, fingerprint)
+	}
+
 	for header, pattern := range fingerprint.Cookies {
 		fingerprint, err := ParsePattern(pattern)
 		if err != nil {
 			continue
 		}
+		key, isPattern, err := parseKeyPattern(header)
+		if err != nil {
+			continue
+		}
+		if isPattern {
+			compiled.cookieKeys = append(compiled.cookieKeys, keyPattern{key: key, pattern: fingerprint})
+			continue
+		}
 		compiled.cookies[header] = fingerprint
 	}
 
@@ -170,6 +248,14 @@ func compileFingerprint(fingerprint *Fingerprint) *CompiledFingerprint {
 		if err != nil {
 			continue
 		}
+		key, isPattern, err := parseKeyPattern(header)
+		if err != nil {
+			continue
+		}
+		if isPattern {
+			compiled.headerKeys = append(compiled.headerKeys, keyPattern{key: key, pattern: fingerprint})
+			continue
+		}
 		compiled.headers[header] = fingerprint
 	}
 
@@ -197,7 +283,69 @@ func compileFingerprint(fingerprint *Fingerprint) *CompiledFingerprint {
 		compiled.scriptSrc = append(compiled.scriptSrc, fingerprint)
 	}
 
+	for _, pattern := range fingerprint.URL {
+		fingerprint, err := ParsePattern(pattern)
+		if err != nil {
+			continue
+		}
+		compiled.url = append(compiled.url, fingerprint)
+	}
+
+	for _, pattern := range fingerprint.CertIssuer {
+		fingerprint, err := ParsePattern(pattern)
+		if err != nil {
+			continue
+		}
+		compiled.certIssuer = append(compiled.certIssuer, fingerprint)
+	}
+
+	for _, pattern := range fingerprint.CertSubject {
+		fingerprint, err := ParsePattern(pattern)
+		if err != nil {
+			continue
+		}
+		compiled.certSubject = append(compiled.certSubject, fingerprint)
+	}
+
+	for _, pattern := range fingerprint.CertSAN {
+		fingerprint, err := ParsePattern(pattern)
+		if err != nil {
+			continue
+		}
+		compiled.certSAN = append(compiled.certSAN, fingerprint)
+	}
+
+	for _, pattern := range fingerprint.Robots {
+		fingerprint, err := ParsePattern(pattern)
+		if err != nil {
+			continue
+		}
+		compiled.robots = append(compiled.robots, fingerprint)
+	}
+
+	for _, pattern := range fingerprint.Text {
+		fingerprint, err := ParsePattern(pattern)
+		if err != nil {
+			continue
+		}
+		compiled.text = append(compiled.text, fingerprint)
+	}
+
+	for path, pattern := range fingerprint.Probe {
+		fingerprint, err := ParsePattern(pattern)
+		if err != nil {
+			continue
+		}
+		compiled.probe[path] = fingerprint
+	}
+
 	for meta, patterns := range fingerprint.Meta {
+		// Rules without patterns only require the meta tag to be present
+		if len(patterns) == 0 {
+			compiled.metaPresence[meta] = struct{}{}
+			continue
+		}
+
 		var compiledList []*ParsedPattern
 
 		for _, pattern := range patterns {
@@ -207,176 +355,176 @@ func compileFingerprint(fingerprint *Fingerprint) *CompiledFingerprint {
 			}
 			compiledList = append(compiledList, fingerprint)
 		}
-		compiled.meta[meta] = compiledList
+		if len(compiledList) > 0 {
+			compiled.meta[meta] = compiledList
+		}
+	}
+
+	for record, patterns := range fingerprint.DNS {
+		var compiledList []*ParsedPattern
+
+		for _, pattern := range patterns {
+			fingerprint, err := ParsePattern(pattern)
+			if err != nil {
+				continue
+			}
+			compiledList = append(compiledList, fingerprint)
+		}
+		compiled.dns[strings.ToUpper(record)] = compiledList
 	}
 	return compiled
 }
 
+// patternMatch accumulates the patterns of a fingerprint matching an input,
+// keeping the highest confidence and the most specific version.
+type patternMatch struct {
+	matched    bool
+	confidence int
+	version    string
+}
+
+// match evaluates patterns against data and accumulates the valid ones
+func (m *patternMatch) match(data string, patterns ...*ParsedPattern) {
+	for _, pattern := range patterns {
+		valid, versionString := pattern.Evaluate(data)
+		if !valid {
+			continue
+		}
+		m.matched = true
+		if pattern.Confidence > m.confidence {
+			m.confidence = pattern.Confidence
+		}
+		if versionString != "" && (m.version == "" || isMoreSpecific(versionString, m.version)) {
+			m.version = versionString
+		}
+	}
+}
+
+// present accumulates a rule only requiring its key to be present
+func (m *patternMatch) present() {
+	m.matched = true
+	m.confidence = 100
+}
+
+// results returns the match results of an app along with its implied apps
+func (m *patternMatch) results(app string, fingerprint *CompiledFingerprint) []matchPartResult {
+	technologies := []matchPartResult{{
+		application: app,
+		version:     m.version,
+		confidence:  m.confidence,
+	}}
+	for _, implies := range fingerprint.implies {
+		technologies = append(technologies, matchPartResult{
+			application: implies,
+			confidence:  m.confidence,
+		})
+	}
+	return technologies
+}
+
+// stringPatterns returns the patterns of a fingerprint matching a string part
+func (f *CompiledFingerprint) stringPatterns(part part) []*ParsedPattern {
+	switch part {
+	case scriptPart:
+		return f.scriptSrc
+	case htmlPart:
+		return f.html
+	case urlPart:
+		return f.url
+	case certIssuerPart:
+		return f.certIssuer
+	case certSubjectPart:
+		return f.certSubject
+	case certSANPart:
+		return f.certSAN
+	case robotsPart:
+		return f.robots
+	case textPart:
+		return f.text
+	case cssPart:
+		return f.css
+	case scriptContentPart:
+		return f.script
+	}
+	return nil
+}
+
 // matchString matches a string for the fingerprints
 func (f *CompiledFingerprints) matchString(data string, part part) []matchPartResult {
-	var matched bool
 	var technologies []matchPartResult
 
 	for app, fingerprint := range f.Apps {
-		var version string
-		var confidence int
-
-		switch part {
-		case jsPart:
+		var result patternMatch
+		if part == jsPart {
 			for _, pattern := range fingerprint.js {
-				if valid, versionString := pattern.Evaluate(data); valid {
-					matched = true
-					if pattern.Confidence > confidence {
-						confidence = pattern.Confidence
-					}
-					if versionString != "" && (version == "" || isMoreSpecific(versionString, version)) {
-						version = versionString
-					}
-				}
-			}
-		case scriptPart:
-			for _, pattern := range fingerprint.scriptSrc {
-				if valid, versionString := pattern.Evaluate(data); valid {
-					matched = true
-					if pattern.Confidence > confidence {
-						confidence = pattern.Confidence
-					}
-					if versionString != "" && (version == "" || isMoreSpecific(versionString, version)) {
-						version = versionString
-					}
-				}
-			}
-		case htmlPart:
-			for _, pattern := range fingerprint.html {
-				if valid, versionString := pattern.Evaluate(data); valid {
-					matched = true
-					if pattern.Confidence > confidence {
-						confidence = pattern.Confidence
-					}
-					if versionString != "" && (version == "" || isMoreSpecific(versionString, version)) {
-						version = versionString
-					}
-				}
+				result.match(data, pattern)
 			}
+		} else {
+			result.match(data, fingerprint.stringPatterns(part)...)
 		}
 
 		// If no match, continue with the next fingerprint
-		if !matched {
+		if !result.matched {
 			continue
 		}
 
 		// Append the technologies as well as implied ones
-		technologies = append(technologies, matchPartResult{
-			application: app,
-			version:     version,
-			confidence:  confidence,
-		})
-		if len(fingerprint.implies) > 0 {
-			for _, implies := range fingerprint.implies {
-				technologies = append(technologies, matchPartResult{
-					application: implies,
-					confidence:  confidence,
-				})
-			}
-		}
-		matched = false
+		technologies = append(technologies, result.results(app, fingerprint)...)
 	}
 	return technologies
 }
 
 // matchKeyValue matches a key-value store map for the fingerprints
 func (f *CompiledFingerprints) matchKeyValueString(key, value string, part part) []matchPartResult {
-	var matched bool
 	var technologies []matchPartResult
 
 	for app, fingerprint := range f.Apps {
-		var version string
-		var confidence int
+		var result patternMatch
 
 		switch part {
 		case cookiesPart:
-			for data, pattern := range fingerprint.cookies {
-				if data != key {
-					continue
-				}
-
-				if valid, versionString := pattern.Evaluate(value); valid {
-					matched = true
-					if pattern.Confidence > confidence {
-						confidence = pattern.Confidence
-					}
-					if versionString != "" && (version == "" || isMoreSpecific(versionString, version)) {
-						version = versionString
-					}
+			if pattern, ok := fingerprint.cookies[key]; ok {
+				result.match(value, pattern)
+			}
+			for _, keyed := range fingerprint.cookieKeys {
+				if keyed.key.MatchString(key) {
+					result.match(value, keyed.pattern)
 				}
 			}
 		case headersPart:
-			for data, pattern := range fingerprint.headers {
-				if data != key {
-					continue
-				}
-
-				if valid, versionString := pattern.Evaluate(value); valid {
-					matched = true
-					if pattern.Confidence > confidence {
-						confidence = pattern.Confidence
-					}
-					if versionString != "" && (version == "" || isMoreSpecific(versionString, version)) {
-						version = versionString
-					}
+			if pattern, ok := fingerprint.headers[key]; ok {
+				result.match(value, pattern)
+			}
+			for _, keyed := range fingerprint.headerKeys {
+				if keyed.key.MatchString(key) {
+					result.match(value, keyed.pattern)
 				}
 			}
 		case metaPart:
-			for data, patterns := range fingerprint.meta {
-				if data != key {
-					continue
-				}
-
-				for _, pattern := range patterns {
-					if valid, versionString := pattern.Evaluate(value); valid {
-						matched = true
-						if pattern.Confidence > confidence {
-							confidence = pattern.Confidence
-						}
-						if versionString != "" && (version == "" || isMoreSpecific(versionString, version)) {
-							version = versionString
-						}
-					}
-				}
+			if _, ok := fingerprint.metaPresence[key]; ok {
+				result.present()
 			}
+			result.match(value, fingerprint.meta[key]...)
+		case dnsPart:
+			result.match(value, fingerprint.dns[key]...)
 		}
 
 		// If no match, continue with the next fingerprint
-		if !matched {
+		if !result.matched {
 			continue
 		}
 
-		technologies = append(technologies, matchPartResult{
-			application: app,
-			version:     version,
-			confidence:  confidence,
-		})
-		if len(fingerprint.implies) > 0 {
-			for _, implies := range fingerprint.implies {
-				technologies = append(technologies, matchPartResult{
-					application: implies,
-					confidence:  confidence,
-				})
-			}
-		}
-		matched = false
+		technologies = append(technologies, result.results(app, fingerprint)...)
 	}
 	return technologies
 }
 
 // matchMapString matches a key-value store map for the fingerprints
 func (f *CompiledFingerprints) matchMapString(keyValue map[string]string, part part) []matchPartResult {
-	var matched bool
 	var technologies []matchPartResult
 
 	for app, fingerprint := range f.Apps {
-		var version string
-		var confidence int
+		var result patternMatch
 
 		switch part {
 		case cookiesPart:
@@ -386,76 +534,50 @@ func (f *CompiledFingerprints) matchMapString(keyValue map[string]string, part p
 					continue
 				}
 				if pattern == nil {
-					matched = true
+					result.matched = true
 					continue
 				}
-				if valid, versionString := pattern.Evaluate(value); valid {
-					matched = true
-					if pattern.Confidence > confidence {
-						confidence = pattern.Confidence
-					}
-					if versionString != "" && (version == "" || isMoreSpecific(versionString, version)) {
-						version = versionString
+				result.match(value, pattern)
+			}
+			for _, keyed := range fingerprint.cookieKeys {
+				for data, value := range keyValue {
+					if keyed.key.MatchString(data) {
+						result.match(value, keyed.pattern)
 					}
 				}
 			}
 		case headersPart:
 			for data, pattern := range fingerprint.headers {
-				value, ok := keyValue[data]
-				if !ok {
-					continue
+				if value, ok := keyValue[data]; ok {
+					result.match(value, pattern)
 				}
-
-				if valid, versionString := pattern.Evaluate(value); valid {
-					matched = true
-					if pattern.Confidence > confidence {
-						confidence = pattern.Confidence
-					}
-					if versionString != "" && (version == "" || isMoreSpecific(versionString, version)) {
-						version = versionString
+			}
+			for _, keyed := range fingerprint.headerKeys {
+				for data, value := range keyValue {
+					if keyed.key.MatchString(data) {
+						result.match(value, keyed.pattern)
 					}
 				}
 			}
 		case metaPart:
-			for data, patterns := range fingerprint.meta {
-				value, ok := keyValue[data]
-				if !ok {
-					continue
+			for data := range fingerprint.metaPresence {
+				if _, ok := keyValue[data]; ok {
+					result.present()
 				}
-
-				for _, pattern := range patterns {
-					if valid, versionString := pattern.Evaluate(value); valid {
-						matched = true
-						if pattern.Confidence > confidence {
-							confidence = pattern.Confidence
-						}
-						if versionString != "" && (version == "" || isMoreSpecific(versionString, version)) {
-							version = versionString
-						}
-					}
+			}
+			for data, patterns := range fingerprint.meta {
+				if value, ok := keyValue[data]; ok {
+					result.match(value, patterns...)
 				}
 			}
 		}
 
 		// If no match, continue with the next fingerprint
-		if !matched {
+		if !result.matched {
 			continue
 		}
 
-		technologies = append(technologies, matchPartResult{
-			application: app,
-			version:     version,
-			confidence:  confidence,
-		})
-		if len(fingerprint.implies) > 0 {
-			for _, implies := range fingerprint.implies {
-				technologies = append(technologies, matchPartResult{
-					application: implies,
-					confidence:  confidence,
-				})
-			}
-		}
-		matched = false
+		technologies = append(technologies, result.results(app, fingerprint)...)
 	}
 	return technologies
 }
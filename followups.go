@@ -0,0 +1,194 @@
+package wappalyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// FollowUpDatabase is a mapping from technologies to the paths worth
+// checking once they are detected, to confirm them or disclose their
+// version, so that passive detection can be chained into targeted
+// verification.
+type FollowUpDatabase struct {
+	// followUps is organized as <technology name, follow-ups>
+	followUps map[string][]*FollowUp
+}
+
+// FollowUp is a path to check on a site for a detected technology.
+type FollowUp struct {
+	Technology string
+	Path       string
+	// Purpose is either FollowUpConfirm or FollowUpVersion
+	Purpose string
+	// Status is the expected response status, any 2xx status if zero
+	Status int
+	// Matcher is the pattern the response body has to match, in the same
+	// format as the fingerprints, empty if the status is enough
+	Matcher string
+
+	matcher *ParsedPattern
+}
+
+// purposes of a follow-up
+const (
+	// FollowUpConfirm follow-ups confirm the presence of a technology
+	FollowUpConfirm = "confirm"
+	// FollowUpVersion follow-ups disclose the version of a technology
+	FollowUpVersion = "version"
+)
+
+type followUpEntry struct {
+	Technology string `json:"technology"`
+	Paths      []struct {
+		Path    string `json:"path"`
+		Purpose string `json:"purpose,omitempty"`
+		Status  int    `json:"status,omitempty"`
+		Matcher string `json:"matcher,omitempty"`
+	} `json:"paths"`
+}
+
+// LoadFollowUpDatabase loads a follow-up database from a JSON file.
+func LoadFollowUpDatabase(filePath string) (*FollowUpDatabase, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewFollowUpDatabase(f)
+}
+
+// NewFollowUpDatabase creates a follow-up database from a JSON list of
+// technologies with their paths, as in:
+//
+//	[{"technology": "WordPress", "paths": [
+//		{"path": "/wp-json/", "matcher": "\"namespaces\""},
+//		{"path": "/readme.html", "purpose": "version", "matcher": "Version ([\\d.]+)\\;version:\\1"}
+//	]}]
+func NewFollowUpDatabase(reader io.Reader) (*FollowUpDatabase, error) {
+	var entries []followUpEntry
+	if err := json.NewDecoder(reader).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	database := &FollowUpDatabase{followUps: make(map[string][]*FollowUp)}
+	for _, entry := range entries {
+		if entry.Technology == "" {
+			return nil, fmt.Errorf("follow-up entry without technology")
+		}
+		for _, path := range entry.Paths {
+			followUp := &FollowUp{
+				Technology: entry.Technology,
+				Path:       path.Path,
+				Purpose:    path.Purpose,
+				Status:     path.Status,
+				Matcher:    path.Matcher,
+			}
+			if err := database.Add(followUp); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return database, nil
+}
+
+// Add adds a follow-up to the database, replacing the
+// follow-up with the same technology and path if any.
+func (d *FollowUpDatabase) Add(followUp *FollowUp) error {
+	if followUp.Technology == "" || !strings.HasPrefix(followUp.Path, "/") {
+		return fmt.Errorf("invalid follow-up path %q for %q", followUp.Path, followUp.Technology)
+	}
+	switch followUp.Purpose {
+	case "":
+		followUp.Purpose = FollowUpConfirm
+	case FollowUpConfirm, FollowUpVersion:
+	default:
+		return fmt.Errorf("invalid follow-up purpose %q for %s", followUp.Purpose, followUp.Technology)
+	}
+	matcher, err := ParsePattern(followUp.Matcher)
+	if err != nil {
+		return fmt.Errorf("invalid follow-up matcher for %s: %w", followUp.Technology, err)
+	}
+	followUp.matcher = matcher
+
+	followUps := d.followUps[followUp.Technology]
+	for i, existing := range followUps {
+		if existing.Path == followUp.Path {
+			followUps[i] = followUp
+			return nil
+		}
+	}
+	d.followUps[followUp.Technology] = append(followUps, followUp)
+	return nil
+}
+
+// AddProbes adds the probe patterns of the fingerprints as
+// confirmation follow-ups, keeping the existing follow-ups.
+func (d *FollowUpDatabase) AddProbes(wappalyzer *Wappalyze) {
+	for app, fingerprint := range wappalyzer.fingerprints.Apps {
+		for path := range fingerprint.probe {
+			if d.has(app, path) || !strings.HasPrefix(path, "/") {
+				continue
+			}
+			d.followUps[app] = append(d.followUps[app], &FollowUp{
+				Technology: app,
+				Path:       path,
+				Purpose:    FollowUpConfirm,
+				Matcher:    wappalyzer.original.Apps[app].Probe[path],
+				matcher:    fingerprint.probe[path],
+			})
+		}
+	}
+}
+
+func (d *FollowUpDatabase) has(technology, path string) bool {
+	for _, followUp := range d.followUps[technology] {
+		if followUp.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggest returns the follow-ups for the technologies detected by a
+// Fingerprint call, sorted by technology and path. Version follow-ups
+// are skipped for technologies detected with a version.
+func (d *FollowUpDatabase) Suggest(apps map[string]struct{}) []*FollowUp {
+	var suggestions []*FollowUp
+	for app := range apps {
+		name, version, _ := strings.Cut(app, versionSeparator)
+		for _, followUp := range d.followUps[name] {
+			if followUp.Purpose == FollowUpVersion && version != "" {
+				continue
+			}
+			suggestions = append(suggestions, followUp)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Technology != suggestions[j].Technology {
+			return suggestions[i].Technology < suggestions[j].Technology
+		}
+		return suggestions[i].Path < suggestions[j].Path
+	})
+	return suggestions
+}
+
+// Match reports whether the response of the follow-up path matches,
+// along with the version disclosed by the matcher if there's any.
+func (f *FollowUp) Match(status int, body []byte) (bool, string) {
+	if f.Status != 0 && status != f.Status {
+		return false, ""
+	}
+	if f.Status == 0 && (status < 200 || status > 299) {
+		return false, ""
+	}
+	if f.matcher == nil {
+		return true, ""
+	}
+	return f.matcher.Evaluate(string(body))
+}
@@ -0,0 +1,63 @@
+package wappalyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowUps(t *testing.T) {
+	database, err := NewFollowUpDatabase(strings.NewReader(`[
+		{"technology": "WordPress", "paths": [
+			{"path": "/wp-json/", "matcher": "\"namespaces\""},
+			{"path": "/readme.html", "purpose": "version", "matcher": "Version ([\\d.]+)\\;version:\\1"}
+		]},
+		{"technology": "Jenkins", "paths": [
+			{"path": "/api/json", "status": 403}
+		]}
+	]`))
+	require.Nil(t, err, "could not create follow-up database")
+
+	paths := func(followUps []*FollowUp) []string {
+		var paths []string
+		for _, followUp := range followUps {
+			paths = append(paths, followUp.Technology+" "+followUp.Path)
+		}
+		return paths
+	}
+
+	suggestions := database.Suggest(map[string]struct{}{"WordPress": {}, "Jenkins": {}, "PHP": {}})
+	require.Equal(t, []string{"Jenkins /api/json", "WordPress /readme.html", "WordPress /wp-json/"}, paths(suggestions), "could not get suggestions")
+
+	suggestions = database.Suggest(map[string]struct{}{"WordPress:6.4.2": {}})
+	require.Equal(t, []string{"WordPress /wp-json/"}, paths(suggestions), "could not skip version follow-ups")
+
+	t.Run("match", func(t *testing.T) {
+		readme := database.followUps["WordPress"][1]
+		matched, version := readme.Match(200, []byte("<br /> Version 6.4.2"))
+		require.True(t, matched, "could not match follow-up")
+		require.Equal(t, "6.4.2", version, "could not get version")
+
+		matched, _ = readme.Match(404, []byte("<br /> Version 6.4.2"))
+		require.False(t, matched, "could match unsuccessful response")
+
+		matched, _ = database.followUps["Jenkins"][0].Match(403, nil)
+		require.True(t, matched, "could not match expected status")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewFollowUpDatabase(strings.NewReader(`[{"technology": "WordPress", "paths": [{"path": "/", "purpose": "exploit"}]}]`))
+		require.NotNil(t, err, "could create follow-up with invalid purpose")
+	})
+
+	t.Run("probes", func(t *testing.T) {
+		wappalyzer := newTestWappalyzer(t, `{
+			"Jenkins": {"probe": {"/login": "Welcome to Jenkins"}}
+		}`)
+		database.AddProbes(wappalyzer)
+
+		suggestions := database.Suggest(map[string]struct{}{"Jenkins": {}})
+		require.Equal(t, []string{"Jenkins /api/json", "Jenkins /login"}, paths(suggestions), "could not add probes")
+	})
+}
@@ -0,0 +1,215 @@
+package wappalyzer
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// HashDatabase is a database of hashes of known library files, used to
+// identify the exact version of libraries from subresource integrity
+// attributes or from the contents of their files.
+type HashDatabase struct {
+	// entries is organized as <algorithm-base64 digest, entry>
+	entries map[string]HashEntry
+	// algorithms contains the hash algorithms used by the entries
+	algorithms map[string]struct{}
+}
+
+// HashEntry is a known library file.
+type HashEntry struct {
+	// Name is the technology name, as used by the fingerprints
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	File    string   `json:"file,omitempty"`
+	Hashes  []string `json:"hashes"`
+}
+
+// hashAlgorithms are the supported subresource integrity algorithms
+var hashAlgorithms = map[string]func([]byte) []byte{
+	"sha256": func(data []byte) []byte {
+		sum := sha256.Sum256(data)
+		return sum[:]
+	},
+	"sha384": func(data []byte) []byte {
+		sum := sha512.Sum384(data)
+		return sum[:]
+	},
+	"sha512": func(data []byte) []byte {
+		sum := sha512.Sum512(data)
+		return sum[:]
+	},
+}
+
+// LoadHashDatabase loads a hash database from a JSON file.
+func LoadHashDatabase(filePath string) (*HashDatabase, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewHashDatabase(f)
+}
+
+// NewHashDatabase creates a hash database from a JSON list of entries.
+//
+// Hashes are written either in subresource integrity form, like
+// "sha384-<base64 digest>", or as "sha384:<hex digest>".
+func NewHashDatabase(reader io.Reader) (*HashDatabase, error) {
+	var entries []HashEntry
+	if err := json.NewDecoder(reader).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	database := &HashDatabase{
+		entries:    make(map[string]HashEntry),
+		algorithms: make(map[string]struct{}),
+	}
+	for _, entry := range entries {
+		if err := database.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return database, nil
+}
+
+// Add adds a known library file to the database.
+func (d *HashDatabase) Add(entry HashEntry) error {
+	if entry.Name == "" {
+		return fmt.Errorf("hash entry without name")
+	}
+	for _, hash := range entry.Hashes {
+		algorithm, digest, err := parseHash(hash)
+		if err != nil {
+			return fmt.Errorf("invalid hash for %s: %w", entry.Name, err)
+		}
+		d.entries[algorithm+"-"+digest] = entry
+		d.algorithms[algorithm] = struct{}{}
+	}
+	return nil
+}
+
+// parseHash parses a hash into its algorithm and base64 digest.
+func parseHash(hash string) (string, string, error) {
+	if algorithm, digest, ok := strings.Cut(hash, ":"); ok {
+		algorithm = strings.ToLower(algorithm)
+		if _, ok := hashAlgorithms[algorithm]; !ok {
+			return "", "", fmt.Errorf("unsupported algorithm %q", algorithm)
+		}
+		decoded, err := hex.DecodeString(digest)
+		if err != nil {
+			return "", "", err
+		}
+		return algorithm, base64.StdEncoding.EncodeToString(decoded), nil
+	}
+
+	algorithm, digest, ok := strings.Cut(hash, "-")
+	if !ok {
+		return "", "", fmt.Errorf("invalid hash %q", hash)
+	}
+	algorithm = strings.ToLower(algorithm)
+	if _, ok := hashAlgorithms[algorithm]; !ok {
+		return "", "", fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+	if _, err := base64.StdEncoding.DecodeString(digest); err != nil {
+		return "", "", err
+	}
+	return algorithm, digest, nil
+}
+
+// Lookup returns the known file matching a subresource integrity
+// attribute, which can contain several space separated hashes.
+func (d *HashDatabase) Lookup(integrity string) (HashEntry, bool) {
+	for _, hash := range strings.Fields(integrity) {
+		// Options can follow the digest, as in sha384-<digest>?opt
+		hash, _, _ = strings.Cut(hash, "?")
+
+		algorithm, digest, ok := strings.Cut(hash, "-")
+		if !ok {
+			continue
+		}
+		if entry, ok := d.entries[strings.ToLower(algorithm)+"-"+digest]; ok {
+			return entry, true
+		}
+	}
+	return HashEntry{}, false
+}
+
+// LookupContent returns the known file with the same contents as data.
+func (d *HashDatabase) LookupContent(data []byte) (HashEntry, bool) {
+	for algorithm := range d.algorithms {
+		digest := base64.StdEncoding.EncodeToString(hashAlgorithms[algorithm](data))
+		if entry, ok := d.entries[algorithm+"-"+digest]; ok {
+			return entry, true
+		}
+	}
+	return HashEntry{}, false
+}
+
+// SetHashDatabase sets the database of known library files used to
+// identify libraries from integrity attributes and script contents.
+func (s *Wappalyze) SetHashDatabase(database *HashDatabase) {
+	s.hashes = database
+}
+
+// FingerprintScripts identifies libraries from the contents of
+// scripts or stylesheets fetched by the caller, using the hash database.
+func (s *Wappalyze) FingerprintScripts(scripts ...[]byte) map[string]struct{} {
+	uniqueFingerprints := NewUniqueFingerprints()
+	for _, script := range scripts {
+		for _, app := range s.checkContentHash(script) {
+			uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+		}
+	}
+	return uniqueFingerprints.GetValues()
+}
+
+// checkContentHash checks the contents of a file against the hash database
+func (s *Wappalyze) checkContentHash(data []byte) []matchPartResult {
+	if s.hashes == nil || len(data) == 0 {
+		return nil
+	}
+	entry, ok := s.hashes.LookupContent(data)
+	if !ok {
+		return nil
+	}
+	return s.hashEntryResults(entry)
+}
+
+// checkIntegrity checks an integrity attribute against the hash database
+func (s *Wappalyze) checkIntegrity(integrity string) []matchPartResult {
+	if s.hashes == nil || integrity == "" {
+		return nil
+	}
+	entry, ok := s.hashes.Lookup(integrity)
+	if !ok {
+		return nil
+	}
+	return s.hashEntryResults(entry)
+}
+
+// hashEntryResults returns the match results for a known file,
+// along with the technologies it implies.
+func (s *Wappalyze) hashEntryResults(entry HashEntry) []matchPartResult {
+	technologies := []matchPartResult{{
+		application: entry.Name,
+		version:     entry.Version,
+		confidence:  100,
+	}}
+	if fingerprint, ok := s.fingerprints.Apps[entry.Name]; ok {
+		for _, implies := range fingerprint.implies {
+			technologies = append(technologies, matchPartResult{
+				application: implies,
+				confidence:  100,
+			})
+		}
+	}
+	return technologies
+}
@@ -0,0 +1,84 @@
+package wappalyzer
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashDatabase(t *testing.T) {
+	jquery := []byte("/*! jQuery v3.7.1 | (c) OpenJS Foundation */")
+	bootstrap := []byte("/*! Bootstrap v5.3.2 */")
+
+	jquerySum := sha512.Sum384(jquery)
+	jqueryIntegrity := "sha384-" + base64.StdEncoding.EncodeToString(jquerySum[:])
+	bootstrapSum := sha256.Sum256(bootstrap)
+
+	database, err := NewHashDatabase(strings.NewReader(fmt.Sprintf(`[
+		{"name": "jQuery", "version": "3.7.1", "file": "jquery.min.js", "hashes": [%q]},
+		{"name": "Bootstrap", "version": "5.3.2", "file": "bootstrap.min.css", "hashes": ["sha256:%s"]}
+	]`, jqueryIntegrity, hex.EncodeToString(bootstrapSum[:]))))
+	require.NoError(t, err, "could not load hash database")
+
+	t.Run("lookup", func(t *testing.T) {
+		entry, ok := database.Lookup("sha512-AAAA " + jqueryIntegrity)
+		require.True(t, ok, "could not lookup integrity")
+		require.Equal(t, "jQuery", entry.Name)
+		require.Equal(t, "3.7.1", entry.Version)
+
+		_, ok = database.Lookup("sha384-AAAA")
+		require.False(t, ok, "could lookup unknown integrity")
+	})
+
+	t.Run("content", func(t *testing.T) {
+		entry, ok := database.LookupContent(bootstrap)
+		require.True(t, ok, "could not lookup content")
+		require.Equal(t, "Bootstrap", entry.Name)
+	})
+
+	t.Run("fingerprint", func(t *testing.T) {
+		wappalyzer, err := New()
+		require.Nil(t, err, "could not create wappalyzer")
+		wappalyzer.SetHashDatabase(database)
+
+		matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(fmt.Sprintf(`<html>
+<head>
+<script src="https://cdn.example.com/lib.js" integrity="%s" crossorigin="anonymous"></script>
+</head>
+</html>`, jqueryIntegrity)))
+		require.Contains(t, matches, "jQuery:3.7.1", "Could not get integrity match")
+
+		matches = wappalyzer.FingerprintScripts([]byte("unknown"), bootstrap)
+		require.Equal(t, map[string]struct{}{"Bootstrap:5.3.2": {}}, matches, "Could not get content match")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{
+			`[{"name": "x", "hashes": ["md5-AAAA"]}]`,
+			`[{"name": "x", "hashes": ["sha256:zz"]}]`,
+			`[{"hashes": ["sha256-AAAA"]}]`,
+		} {
+			_, err := NewHashDatabase(strings.NewReader(input))
+			require.Error(t, err, "could load invalid database %s", input)
+		}
+	})
+}
+
+func TestLoadHashDatabase(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "hashes.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`[{"name": "jQuery", "version": "1.0", "hashes": ["sha256-AAAA"]}]`), 0o600))
+
+	database, err := LoadHashDatabase(filePath)
+	require.NoError(t, err, "could not load hash database")
+	entry, ok := database.Lookup("sha256-AAAA")
+	require.True(t, ok)
+	require.Equal(t, "1.0", entry.Version)
+}
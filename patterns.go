@@ -2,15 +2,54 @@ package wappalyzer
 
 import (
 	"fmt"
+	"math"
 	"regexp"
+	"regexp/syntax"
 	"strconv"
 	"strings"
 )
 
+// keyPattern is a cookie or header fingerprint whose name is
+// a wildcard or regular expression rather than an exact name.
+type keyPattern struct {
+	key     *regexp.Regexp
+	pattern *ParsedPattern
+}
+
+// parseKeyPattern parses a cookie or header name into a regular expression
+// matching whole lowercased names, if it's not an exact name.
+//
+// Names starting with "^" or "(?" are regular expressions, while names
+// containing "*" are wildcards where "*" matches any characters. Other
+// names, including ones with brackets like "mybb[lastvisit]", are exact.
+func parseKeyPattern(key string) (*regexp.Regexp, bool, error) {
+	var expression string
+	switch {
+	case strings.HasPrefix(key, "^") || strings.HasPrefix(key, "(?"):
+		expression = "(?i)^(?:" + strings.TrimSuffix(strings.TrimPrefix(key, "^"), "$") + ")$"
+	case strings.Contains(key, "*"):
+		parts := strings.Split(key, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		expression = "(?i)^" + strings.Join(parts, ".*") + "$"
+	default:
+		return nil, false, nil
+	}
+
+	regex, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, false, err
+	}
+	return regex, true, nil
+}
+
 // ParsedPattern encapsulates a regular expression with
 // additional metadata for confidence and version extraction.
 type ParsedPattern struct {
 	regex *regexp.Regexp
+	// secondary verifies constructs RE2 cannot express, if any
+	secondary *secondaryMatcher
 
 	Confidence int
 	Version    string
@@ -18,13 +57,11 @@ type ParsedPattern struct {
 }
 
 const (
-	verCap1        = `(\d+(?:\.\d+)+)` // captures 1 set of digits '\d+' followed by one or more '\.\d+' patterns
-	verCap1Fill    = "__verCap1__"
-	verCap1Limited = `(\d{1,20}(?:\.\d{1,20}){1,20})`
-
-	verCap2        = `((?:\d+\.)+\d+)` // captures 1 or more '\d+\.' patterns followed by 1 set of digits '\d+'
-	verCap2Fill    = "__verCap2__"
-	verCap2Limited = `((?:\d{1,20}\.){1,20}\d{1,20})`
+	// maxRepeat is the upper bound for unbounded quantifiers like + and *
+	maxRepeat = 250
+	// maxRepeatProduct is the maximum product of nested repeat counts
+	// accepted by the regexp package.
+	maxRepeatProduct = 1000
 )
 
 // ParsePattern extracts information from a pattern, supporting both regex and simple patterns
@@ -40,26 +77,22 @@ func ParsePattern(pattern string) (*ParsedPattern, error) {
 			if p.SkipRegex {
 				continue
 			}
-			regexPattern := part
-
-			// save version capture groups
-			regexPattern = strings.ReplaceAll(regexPattern, verCap1, verCap1Fill)
-			regexPattern = strings.ReplaceAll(regexPattern, verCap2, verCap2Fill)
-
-			regexPattern = strings.ReplaceAll(regexPattern, "\\+", "__escapedPlus__")
-			regexPattern = strings.ReplaceAll(regexPattern, "+", "{1,250}")
-			regexPattern = strings.ReplaceAll(regexPattern, "*", "{0,250}")
-			regexPattern = strings.ReplaceAll(regexPattern, "__escapedPlus__", "\\+")
-
-			// restore version capture groups
-			regexPattern = strings.ReplaceAll(regexPattern, verCap1Fill, verCap1Limited)
-			regexPattern = strings.ReplaceAll(regexPattern, verCap2Fill, verCap2Limited)
+			regexPattern, secondary := translatePattern(part)
+			regexPattern, err := boundQuantifiers(regexPattern)
+			if err != nil {
+				return nil, err
+			}
 
-			var err error
 			p.regex, err = regexp.Compile("(?i)" + regexPattern)
 			if err != nil {
 				return nil, err
 			}
+			if secondary != nil {
+				if err := secondary.compile(p.regex); err != nil {
+					return nil, err
+				}
+				p.secondary = secondary
+			}
 		} else {
 			keyValue := strings.SplitN(part, ":", 2)
 			if len(keyValue) < 2 {
@@ -83,6 +116,94 @@ func ParsePattern(pattern string) (*ParsedPattern, error) {
 	return p, nil
 }
 
+// boundQuantifiers replaces unbounded quantifiers with bounded ones
+// to keep matching of large inputs fast.
+//
+// The pattern is parsed into a syntax tree so that only real repetition
+// operators are rewritten, and nested repetitions share the repeat count
+// budget of the regexp package.
+func boundQuantifiers(regexPattern string) (string, error) {
+	re, err := syntax.Parse(regexPattern, syntax.Perl|syntax.FoldCase)
+	if err != nil {
+		return "", err
+	}
+	boundRepeats(re, maxRepeatProduct)
+
+	var builder strings.Builder
+	writeRegexp(&builder, re)
+	return builder.String(), nil
+}
+
+// boundRepeats bounds the repetition operators of re, keeping the product
+// of nested repeat counts within budget.
+func boundRepeats(re *syntax.Regexp, budget int) {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpRepeat:
+	default:
+		for _, sub := range re.Sub {
+			boundRepeats(sub, budget)
+		}
+		return
+	}
+
+	limit := re.Max
+	if re.Op != syntax.OpRepeat || re.Max == -1 {
+		// Split the budget between this repeat and the unbounded ones
+		// nested in it, leaving room for the explicit ones.
+		unbounded, explicit := nestedRepeats(re.Sub[0])
+		available := budget / explicit
+		limit = int(math.Pow(float64(available), 1/float64(unbounded+1)))
+		if limit > maxRepeat {
+			limit = maxRepeat
+		}
+
+		min := 0
+		switch {
+		case re.Op == syntax.OpPlus:
+			min = 1
+		case re.Op == syntax.OpRepeat:
+			min = re.Min
+		}
+		if limit < min {
+			limit = min
+		}
+		if limit < 1 {
+			limit = 1
+		}
+		re.Op, re.Min, re.Max = syntax.OpRepeat, min, limit
+	}
+
+	if limit > 0 {
+		budget /= limit
+	}
+	boundRepeats(re.Sub[0], budget)
+}
+
+// nestedRepeats returns the deepest chain of unbounded repeats in re and
+// the largest product of explicit repeat counts along any chain.
+func nestedRepeats(re *syntax.Regexp) (unbounded int, explicit int) {
+	explicit = 1
+	for _, sub := range re.Sub {
+		subUnbounded, subExplicit := nestedRepeats(sub)
+		if subUnbounded > unbounded {
+			unbounded = subUnbounded
+		}
+		if subExplicit > explicit {
+			explicit = subExplicit
+		}
+	}
+
+	switch {
+	case re.Op == syntax.OpStar, re.Op == syntax.OpPlus:
+		unbounded++
+	case re.Op == syntax.OpRepeat && re.Max == -1:
+		unbounded++
+	case re.Op == syntax.OpRepeat && re.Max > 1:
+		explicit *= re.Max
+	}
+	return unbounded, explicit
+}
+
 func (p *ParsedPattern) Evaluate(target string) (bool, string) {
 	if p.SkipRegex {
 		return true, ""
@@ -91,7 +212,12 @@ func (p *ParsedPattern) Evaluate(target string) (bool, string) {
 		return false, ""
 	}
 
-	submatches := p.regex.FindStringSubmatch(target)
+	var submatches []string
+	if p.secondary != nil {
+		submatches = p.secondary.match(p.regex, target)
+	} else {
+		submatches = p.regex.FindStringSubmatch(target)
+	}
 	if len(submatches) == 0 {
 		return false, ""
 	}
@@ -105,47 +231,95 @@ func (p *ParsedPattern) extractVersion(submatches []string) (string, error) {
 		return "", nil // No matches found
 	}
 
-	result := p.Version
-	for i, match := range submatches[1:] { // Start from 1 to skip the entire match
-		placeholder := fmt.Sprintf("\\%d", i+1)
-		result = strings.ReplaceAll(result, placeholder, match)
-	}
-
-	// Evaluate any ternary expressions in the result
-	result, err := evaluateVersionExpression(result, submatches[1:])
+	result, err := evaluateVersionExpression(p.Version, submatches)
 	if err != nil {
 		return "", err
 	}
 	return strings.TrimSpace(result), nil
 }
 
-// evaluateVersionExpression handles ternary expressions in version strings.
+// evaluateVersionExpression evaluates a wappalyzer version directive
+// against the submatches of a pattern, submatches[0] being the full match.
+//
+// The grammar follows the upstream implementation:
+//   - \N is replaced with the Nth capture group, or nothing if it did not match.
+//   - \N?a:b evaluates to a if the Nth capture group matched a non-empty
+//     string and to b otherwise. The ternary extends to the end of the
+//     expression, so b can itself contain another ternary, as in \1?\1:\2?\2:\3.
+//   - \\, \: and \? are a literal backslash, colon and question mark.
 func evaluateVersionExpression(expression string, submatches []string) (string, error) {
-	if strings.Contains(expression, "?") {
-		parts := strings.Split(expression, "?")
-		if len(parts) != 2 {
-			return "", fmt.Errorf("invalid ternary expression: %s", expression)
-		}
+	var builder strings.Builder
 
-		trueFalseParts := strings.Split(parts[1], ":")
-		if len(trueFalseParts) != 2 {
-			return "", fmt.Errorf("invalid true/false parts in ternary expression: %s", expression)
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		if c != '\\' || i+1 >= len(expression) {
+			builder.WriteByte(c)
+			continue
 		}
 
-		if trueFalseParts[0] != "" { // Simple existence check
-			if len(submatches) == 0 {
-				return trueFalseParts[1], nil
+		next := expression[i+1]
+		if !isDigit(next) {
+			if next == '\\' || next == ':' || next == '?' {
+				builder.WriteByte(next)
+			} else {
+				builder.WriteByte(c)
+				builder.WriteByte(next)
 			}
-			return trueFalseParts[0], nil
+			i++
+			continue
 		}
-		if trueFalseParts[1] == "" {
-			if len(submatches) == 0 {
-				return "", nil
-			}
-			return trueFalseParts[0], nil
+
+		group := submatchValue(submatches, int(next-'0'))
+		i++
+		if i+1 >= len(expression) || expression[i+1] != '?' {
+			builder.WriteString(group)
+			continue
+		}
+
+		// Ternary expression, the true branch ends at the first
+		// unescaped colon and the false branch takes the rest.
+		rest := expression[i+2:]
+		separator := ternarySeparator(rest)
+		if separator == -1 {
+			return "", fmt.Errorf("invalid ternary expression: %s", expression)
+		}
+
+		var branch string
+		var err error
+		if group != "" {
+			branch, err = evaluateVersionExpression(rest[:separator], submatches)
+		} else {
+			branch, err = evaluateVersionExpression(rest[separator+1:], submatches)
+		}
+		if err != nil {
+			return "", err
 		}
-		return trueFalseParts[1], nil
+		builder.WriteString(branch)
+		return builder.String(), nil
 	}
+	return builder.String(), nil
+}
+
+// ternarySeparator returns the index of the first unescaped colon in s.
+func ternarySeparator(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ':':
+			return i
+		}
+	}
+	return -1
+}
+
+func submatchValue(submatches []string, index int) string {
+	if index >= len(submatches) {
+		return ""
+	}
+	return submatches[index]
+}
 
-	return expression, nil
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
 }
@@ -0,0 +1,187 @@
+package wappalyzer
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// writeRegexp serializes a parsed pattern back into regexp syntax.
+//
+// syntax.Regexp.String is not used since it computes the minimal set of
+// flags for every node, which involves case folding every character class
+// and is too slow for compiling all of the fingerprints. Patterns are
+// always compiled case insensitive so folding does not need to be kept.
+func writeRegexp(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		b.WriteString(`[^\x00-\x{10FFFF}]`)
+	case syntax.OpEmptyMatch:
+		b.WriteString(`(?:)`)
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 {
+				r = unicode.ToLower(r)
+			}
+			writeLiteral(b, r)
+		}
+	case syntax.OpCharClass:
+		writeCharClass(b, re.Rune)
+	case syntax.OpAnyCharNotNL:
+		b.WriteString(`.`)
+	case syntax.OpAnyChar:
+		b.WriteString(`(?s:.)`)
+	case syntax.OpBeginLine:
+		b.WriteString(`(?m:^)`)
+	case syntax.OpEndLine:
+		b.WriteString(`(?m:$)`)
+	case syntax.OpBeginText:
+		b.WriteString(`^`)
+	case syntax.OpEndText:
+		if re.Flags&syntax.WasDollar != 0 {
+			b.WriteString(`$`)
+		} else {
+			b.WriteString(`\z`)
+		}
+	case syntax.OpWordBoundary:
+		b.WriteString(`\b`)
+	case syntax.OpNoWordBoundary:
+		b.WriteString(`\B`)
+	case syntax.OpCapture:
+		if re.Name != "" {
+			b.WriteString(`(?P<` + re.Name + `>`)
+		} else {
+			b.WriteString(`(`)
+		}
+		writeRegexp(b, re.Sub[0])
+		b.WriteString(`)`)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		writeGrouped(b, re.Sub[0], needsGroupForRepeat(re.Sub[0]))
+		switch re.Op {
+		case syntax.OpStar:
+			b.WriteString(`*`)
+		case syntax.OpPlus:
+			b.WriteString(`+`)
+		case syntax.OpQuest:
+			b.WriteString(`?`)
+		case syntax.OpRepeat:
+			switch {
+			case re.Max == -1:
+				fmt.Fprintf(b, "{%d,}", re.Min)
+			case re.Min == re.Max:
+				fmt.Fprintf(b, "{%d}", re.Min)
+			default:
+				fmt.Fprintf(b, "{%d,%d}", re.Min, re.Max)
+			}
+		}
+		if re.Flags&syntax.NonGreedy != 0 {
+			b.WriteString(`?`)
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeGrouped(b, sub, sub.Op == syntax.OpAlternate)
+		}
+	case syntax.OpAlternate:
+		for i, sub := range re.Sub {
+			if i > 0 {
+				b.WriteString(`|`)
+			}
+			writeRegexp(b, sub)
+		}
+	}
+}
+
+func writeGrouped(b *strings.Builder, re *syntax.Regexp, group bool) {
+	if group {
+		b.WriteString(`(?:`)
+	}
+	writeRegexp(b, re)
+	if group {
+		b.WriteString(`)`)
+	}
+}
+
+// needsGroupForRepeat reports whether re has to be wrapped
+// in a group to be the operand of a repetition.
+func needsGroupForRepeat(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune) > 1
+	case syntax.OpConcat, syntax.OpAlternate, syntax.OpEmptyMatch,
+		syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		return true
+	}
+	return false
+}
+
+func writeLiteral(b *strings.Builder, r rune) {
+	switch {
+	case r < utf8.RuneSelf && strings.ContainsRune(`\.+*?()|[]{}^$`, r):
+		b.WriteByte('\\')
+		b.WriteRune(r)
+	case unicode.IsPrint(r):
+		b.WriteRune(r)
+	default:
+		writeEscapedRune(b, r)
+	}
+}
+
+func writeCharClass(b *strings.Builder, ranges []rune) {
+	if len(ranges) == 0 {
+		b.WriteString(`[^\x00-\x{10FFFF}]`)
+		return
+	}
+	if len(ranges) == 2 && ranges[0] == 0 && ranges[1] == unicode.MaxRune {
+		b.WriteString(`(?s:.)`)
+		return
+	}
+	b.WriteByte('[')
+	// Negated classes are stored as their complement, print
+	// them negated again to keep them readable.
+	if ranges[0] == 0 && ranges[len(ranges)-1] == unicode.MaxRune {
+		b.WriteByte('^')
+		ranges = complementRanges(ranges)
+	}
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		writeClassRune(b, lo)
+		if hi > lo+1 {
+			b.WriteByte('-')
+		}
+		if hi > lo {
+			writeClassRune(b, hi)
+		}
+	}
+	b.WriteByte(']')
+}
+
+// complementRanges returns the ranges not covered by a sorted
+// list of ranges starting at 0 and ending at unicode.MaxRune.
+func complementRanges(ranges []rune) []rune {
+	complement := make([]rune, 0, len(ranges))
+	for i := 1; i+1 < len(ranges); i += 2 {
+		complement = append(complement, ranges[i]+1, ranges[i+1]-1)
+	}
+	return complement
+}
+
+func writeClassRune(b *strings.Builder, r rune) {
+	switch {
+	case r < utf8.RuneSelf && strings.ContainsRune(`\[]^-`, r):
+		b.WriteByte('\\')
+		b.WriteRune(r)
+	case unicode.IsPrint(r):
+		b.WriteRune(r)
+	default:
+		writeEscapedRune(b, r)
+	}
+}
+
+func writeEscapedRune(b *strings.Builder, r rune) {
+	b.WriteString(`\x{`)
+	b.WriteString(strconv.FormatInt(int64(r), 16))
+	b.WriteString(`}`)
+}
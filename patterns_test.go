@@ -14,43 +14,67 @@ func TestParsePattern(t *testing.T) {
 		{
 			name:          "Basic pattern",
 			input:         "Mage.*",
-			expectedRegex: "(?i)Mage.{0,250}",
+			expectedRegex: "(?i)mage.{0,250}",
 			expectedConf:  100,
 		},
 		{
 			name:          "With confidence",
 			input:         "Mage.*\\;confidence:50",
-			expectedRegex: "(?i)Mage.{0,250}",
+			expectedRegex: "(?i)mage.{0,250}",
 			expectedConf:  50,
 		},
 		{
 			name:          "With version",
 			input:         "jquery-([0-9.]+)\\.js\\;version:\\1",
-			expectedRegex: "(?i)jquery-([0-9.]{1,250})\\.js",
+			expectedRegex: "(?i)jquery-([.0-9]{1,250})\\.js",
 			expectedConf:  100,
 			expectedVer:   "\\1",
 		},
 		{
 			name:          "Complex pattern - 1",
 			input:         "/wp-content/themes/make(?:-child)?/.+frontend\\.js(?:\\?ver=(\\d+(?:\\.\\d+)+))?\\;version:\\1",
-			expectedRegex: `(?i)/wp-content/themes/make(?:-child)?/.{1,250}frontend\.js(?:\?ver=(\d{1,20}(?:\.\d{1,20}){1,20}))?`,
+			expectedRegex: `(?i)/wp-content/themes/make(?:-child)?/.{1,250}frontend\.js(?:\?ver=([0-9]{1,250}(?:\.[0-9]{1,32}){1,31}))?`,
 			expectedConf:  100,
 			expectedVer:   "\\1",
 		},
 		{
 			name:          "Complex pattern - 2",
 			input:         "(?:((?:\\d+\\.)+\\d+)\\/)?chroma(?:\\.min)?\\.js\\;version:\\1",
-			expectedRegex: `(?i)(?:((?:\d{1,20}\.){1,20}\d{1,20})\/)?chroma(?:\.min)?\.js`,
+			expectedRegex: `(?i)(?:((?:[0-9]{1,32}\.){1,31}[0-9]{1,250})/)?chroma(?:\.min)?\.js`,
 			expectedConf:  100,
 			expectedVer:   "\\1",
 		},
 		{
 			name:          "Complex pattern - 3",
 			input:         "(?:((?:\\d+\\.)+\\d+)\\/(?:dc\\/)?)?dc(?:\\.leaflet)?\\.js\\;version:\\1",
-			expectedRegex: `(?i)(?:((?:\d{1,20}\.){1,20}\d{1,20})\/(?:dc\/)?)?dc(?:\.leaflet)?\.js`,
+			expectedRegex: `(?i)(?:((?:[0-9]{1,32}\.){1,31}[0-9]{1,250})/(?:dc/)?)?dc(?:\.leaflet)?\.js`,
 			expectedConf:  100,
 			expectedVer:   "\\1",
 		},
+		{
+			name:          "Quantifier characters in class",
+			input:         "[+*]x\\\\+",
+			expectedRegex: `(?i)[*+]x\\{1,250}`,
+			expectedConf:  100,
+		},
+		{
+			name:          "Explicit and nested repeats",
+			input:         "(?:[^/]+/)*content/.{0,32}\\.js",
+			expectedRegex: `(?i)(?:[^/]{1,32}/){0,31}content/.{0,32}\.js`,
+			expectedConf:  100,
+		},
+		{
+			name:          "Open ended repeat",
+			input:         "a{2,}b",
+			expectedRegex: `(?i)a{2,250}b`,
+			expectedConf:  100,
+		},
+		{
+			name:          "Non-greedy repeat",
+			input:         "v(.+?)\\.js",
+			expectedRegex: `(?i)v(.{1,250}?)\.js`,
+			expectedConf:  100,
+		},
 	}
 
 	for _, tt := range tests {
@@ -189,3 +213,277 @@ func TestExtractVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatePattern(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		target      string
+		shouldMatch bool
+		expectedVer string
+	}{
+		{
+			name:        "Trailing literal negative lookahead - match",
+			pattern:     "\\.acquire\\.io/(?!cobrowse)",
+			target:      "https://cdn.acquire.io/widget.js",
+			shouldMatch: true,
+		},
+		{
+			name:    "Trailing literal negative lookahead - no match",
+			pattern: "\\.acquire\\.io/(?!cobrowse)",
+			target:  "https://cdn.acquire.io/cobrowse.js",
+		},
+		{
+			name:        "Trailing positive lookahead",
+			pattern:     "jquery(?=\\.min\\.js)",
+			target:      "/js/jquery.min.js",
+			shouldMatch: true,
+		},
+		{
+			name:        "Leading negative lookahead - match",
+			pattern:     "(?!angular\\.io)\\bangular.{0,32}\\.js",
+			target:      "/lib/angular.min.js",
+			shouldMatch: true,
+		},
+		{
+			name:    "Leading negative lookahead - no match",
+			pattern: "^(?!.*player).*aniview\\.com/",
+			target:  "https://player.aniview.com/script.js",
+		},
+		{
+			name:        "Negative lookahead in the middle",
+			pattern:     "/sites/(?!(?:default|all)/).*/(?:files|themes|modules)/",
+			target:      "/sites/example.com/files/logo.png",
+			shouldMatch: true,
+		},
+		{
+			name:    "Negative lookahead in the middle - no match",
+			pattern: "/sites/(?!(?:default|all)/).*/(?:files|themes|modules)/",
+			target:  "/sites/default/files/logo.png",
+		},
+		{
+			name:        "Negative lookbehind - match",
+			pattern:     "(?<!elo\\.io)/cargo\\.",
+			target:      "https://example.com/cargo.js",
+			shouldMatch: true,
+		},
+		{
+			name:    "Negative lookbehind - no match",
+			pattern: "(?<!elo\\.io)/cargo\\.",
+			target:  "https://elo.io/cargo.js",
+		},
+		{
+			name:        "Lookahead keeps version groups",
+			pattern:     "(?!test)lib-([\\d.]+)\\.js\\;version:\\1",
+			target:      "/lib-1.2.3.js",
+			shouldMatch: true,
+			expectedVer: "1.2.3",
+		},
+		{
+			name:        "Range next to class escape",
+			pattern:     "v([\\d\\.-\\w]+)\\;version:\\1",
+			target:      "v2.1-beta",
+			shouldMatch: true,
+			expectedVer: "2.1-beta",
+		},
+		{
+			name:        "Backreference - match",
+			pattern:     "<(b|i)>x</\\1>",
+			target:      "<b>x</b>",
+			shouldMatch: true,
+		},
+		{
+			name:    "Backreference - no match",
+			pattern: "<(b|i)>x</\\1>",
+			target:  "<b>x</i>",
+		},
+		{
+			name:    "Backreference - extra characters",
+			pattern: "(a)\\1c",
+			target:  "aaxc",
+		},
+		{
+			name:        "Backreference - exact length",
+			pattern:     "(a)\\1c",
+			target:      "aaxc aac",
+			shouldMatch: true,
+		},
+		{
+			name:        "Backreference - trailing",
+			pattern:     "(['\"])foo\\1",
+			target:      "x \"foo\" y",
+			shouldMatch: true,
+		},
+		{
+			name:        "Backreference - trailing repeated group",
+			pattern:     "(a+)-\\1",
+			target:      "aa-aa",
+			shouldMatch: true,
+		},
+		{
+			name:    "Backreference - trailing no match",
+			pattern: "(['\"])foo\\1",
+			target:  "x \"foo' y",
+		},
+		{
+			name:        "Backreference - trailing with version",
+			pattern:     "(['\"])v([\\d.]+)\\1\\;version:\\2",
+			target:      "ver='v1.2.3'",
+			shouldMatch: true,
+			expectedVer: "1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePattern(tt.pattern)
+			if err != nil {
+				t.Fatal("Failed to parse pattern:", err)
+			}
+
+			match, ver := p.Evaluate(tt.target)
+			if match != tt.shouldMatch {
+				t.Errorf("Expected match = %v, got %v", tt.shouldMatch, match)
+				return
+			}
+			if ver != tt.expectedVer {
+				t.Errorf("Expected version = %s, got %s", tt.expectedVer, ver)
+			}
+		})
+	}
+}
+
+func TestEvaluateVersionExpression(t *testing.T) {
+	tests := []struct {
+		name        string
+		expression  string
+		submatches  []string
+		expectedVer string
+		expectError bool
+	}{
+		{"Plain reference", "\\1", []string{"a1.2", "1.2"}, "1.2", false},
+		{"Concatenation", "\\1.\\2.\\3", []string{"x", "1", "9", "3"}, "1.9.3", false},
+		{"Whole match", "\\0", []string{"1.2"}, "1.2", false},
+		{"Missing group", "\\1.\\2", []string{"x", "1"}, "1.", false},
+		{"Ternary on second group", "\\2?yes:no", []string{"x", "a", ""}, "no", false},
+		{"Chained ternaries", "\\1?\\1:\\2?\\2:\\3", []string{"x", "", "", "3.0"}, "3.0", false},
+		{"Prefix before ternary", "v\\1?\\1:0", []string{"x", "2"}, "v2", false},
+		{"Escaped characters", "\\1\\:\\?\\\\", []string{"x", "1"}, "1:?\\", false},
+		{"Escaped colon in branch", "\\1?a\\:b:c", []string{"x", "1"}, "a:b", false},
+		{"Invalid ternary", "\\1?a", []string{"x", "1"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ver, err := evaluateVersionExpression(tt.expression, tt.submatches)
+			if (err != nil) != tt.expectError {
+				t.Errorf("evaluateVersionExpression() error = %v, expectError %v", err, tt.expectError)
+				return
+			}
+			if ver != tt.expectedVer {
+				t.Errorf("Expected version = %s, got %s", tt.expectedVer, ver)
+			}
+		})
+	}
+}
+
+func TestVersionExpressionFingerprints(t *testing.T) {
+	// Patterns taken from fingerprints_data.json
+	tests := []struct {
+		name        string
+		pattern     string
+		target      string
+		expectedVer string
+	}{
+		{
+			name:        "Acquia Cloud Platform",
+			pattern:     "^(next)?.*$\\;version:\\1?next:",
+			target:      "prod",
+			expectedVer: "",
+		},
+		{
+			name:        "Acquia Cloud Platform - next",
+			pattern:     "^(next)?.*$\\;version:\\1?next:",
+			target:      "next-prod",
+			expectedVer: "next",
+		},
+		{
+			name:        "Magento - enterprise",
+			pattern:     "skin/frontend/(?:default|(enterprise))\\;version:\\1?1 (enterprise):1 (community)",
+			target:      "/skin/frontend/enterprise/default/css/styles.css",
+			expectedVer: "1 (enterprise)",
+		},
+		{
+			name:        "Magento - community",
+			pattern:     "skin/frontend/(?:default|(enterprise))\\;version:\\1?1 (enterprise):1 (community)",
+			target:      "/skin/frontend/default/default/css/styles.css",
+			expectedVer: "1 (community)",
+		},
+		{
+			name:        "Shopware",
+			pattern:     "(?:(shopware)|/web/cache/[0-9]{10}_.+)\\.js\\;version:\\1?4:5",
+			target:      "/web/cache/1234567890_main.js",
+			expectedVer: "5",
+		},
+		{
+			name:        "jQuery Migrate - ver parameter",
+			pattern:     "jquery[.-]migrate(?:-([\\d.]+))?(?:\\.min)?\\.js(?:\\?ver=([\\d.]+))?\\;version:\\1?\\1:\\2",
+			target:      "/wp-includes/js/jquery/jquery-migrate.min.js?ver=3.4.1",
+			expectedVer: "3.4.1",
+		},
+		{
+			name:        "jQuery Migrate - file name",
+			pattern:     "jquery[.-]migrate(?:-([\\d.]+))?(?:\\.min)?\\.js(?:\\?ver=([\\d.]+))?\\;version:\\1?\\1:\\2",
+			target:      "/js/jquery-migrate-1.2.1.min.js?ver=5.0",
+			expectedVer: "1.2.1",
+		},
+		{
+			name:        "MoinMoin",
+			pattern:     "moin(?:_static(\\d)(\\d)(\\d)|.+)/common/js/common\\.js\\;version:\\1.\\2.\\3",
+			target:      "/moin_static198/common/js/common.js",
+			expectedVer: "1.9.8",
+		},
+		{
+			name:        "TrustCommander",
+			pattern:     "\\.trustcommander\\.net/privacy/.+_v([\\d]+)_([\\d]+)\\.js\\;version:\\1.\\2",
+			target:      "cdn.trustcommander.net/privacy/2358/privacy_v2_31.js",
+			expectedVer: "2.31",
+		},
+		{
+			name:        "All in One SEO Pack",
+			pattern:     "<!-- all in one seo pro ([\\d.]+) \\;version:pro \\1",
+			target:      "<!-- all in one seo pro 4.2.1 -->",
+			expectedVer: "pro 4.2.1",
+		},
+		{
+			name:        "Google Analytics",
+			pattern:     "gtag\\([^)]+'(g-)\\;version:\\1?ga4:",
+			target:      "gtag('config', 'g-abc123')",
+			expectedVer: "ga4",
+		},
+		{
+			name:        "Axios",
+			pattern:     "/axios(@|/)([\\d.]+)(?:/[a-z]+)?/axios(?:.min)?\\.js\\;version:\\2",
+			target:      "https://cdn.jsdelivr.net/npm/axios@1.6.2/dist/axios.min.js",
+			expectedVer: "1.6.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePattern(tt.pattern)
+			if err != nil {
+				t.Fatal("Failed to parse pattern:", err)
+			}
+
+			match, ver := p.Evaluate(tt.target)
+			if !match {
+				t.Errorf("Failed to match pattern %s with target %s", tt.pattern, tt.target)
+				return
+			}
+			if ver != tt.expectedVer {
+				t.Errorf("Expected version = %s, got %s", tt.expectedVer, ver)
+			}
+		})
+	}
+}
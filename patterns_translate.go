@@ -0,0 +1,415 @@
+package wappalyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Wappalyzer patterns are written for JavaScript regular expressions while
+// Go uses RE2, which has no support for lookarounds and backreferences.
+//
+// translatePattern rewrites the constructs that have an RE2 equivalent
+// (trailing positive lookaheads, trailing literal negative lookaheads and
+// ranges next to class escapes) and replaces everything else with empty
+// marker groups. The markers record where the unsupported construct was in
+// the match and are verified by a bounded secondary matcher after the main
+// regex has matched.
+
+const (
+	// internalGroupPrefix is the prefix of capture groups added during
+	// translation, they are hidden from version extraction.
+	internalGroupPrefix = "__wpz"
+
+	// maxSecondaryCandidates is the maximum number of regex matches
+	// checked by the secondary matcher before giving up.
+	maxSecondaryCandidates = 16
+	// lookbehindWindow is the maximum number of bytes before a match
+	// considered when checking a lookbehind.
+	lookbehindWindow = 250
+	// backreferenceLimit is the maximum length of a backreference.
+	backreferenceLimit = 250
+)
+
+// secondaryMatcher validates lookarounds and backreferences which could
+// not be rewritten into RE2 syntax.
+type secondaryMatcher struct {
+	assertions     []*assertion
+	backreferences []*backreference
+
+	// groups maps original capture group numbers to the group
+	// numbers of the translated regex.
+	groups []int
+}
+
+// assertion is a lookaround checked at the position of its marker group.
+type assertion struct {
+	group    string
+	pattern  string
+	negative bool
+	behind   bool
+
+	index int
+	regex *regexp.Regexp
+}
+
+// backreference is a reference to an earlier capture group replaced
+// by a bounded capture group with the same name prefix.
+type backreference struct {
+	group       string
+	ref         int
+	placeholder string
+
+	index int
+}
+
+// translatePattern rewrites a javascript pattern into a RE2 compatible one.
+// A secondary matcher is returned if some constructs need to be verified
+// after matching.
+func translatePattern(pattern string) (string, *secondaryMatcher) {
+	t := &translator{input: pattern, secondary: &secondaryMatcher{}}
+	output := t.translate()
+	if len(t.secondary.assertions) == 0 && len(t.secondary.backreferences) == 0 {
+		return output, nil
+	}
+	return output, t.secondary
+}
+
+type translator struct {
+	input     string
+	secondary *secondaryMatcher
+	markers   int
+}
+
+func (t *translator) nextGroup() string {
+	name := internalGroupPrefix + strconv.Itoa(t.markers)
+	t.markers++
+	return name
+}
+
+func (t *translator) translate() string {
+	var builder strings.Builder
+
+	input := t.input
+	inClass := false
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+
+		switch {
+		case c == '\\' && i+1 < len(input):
+			next := input[i+1]
+			if !inClass && next >= '1' && next <= '9' {
+				ref := int(next - '0')
+				group := t.nextGroup()
+				placeholder := fmt.Sprintf("(?P<%s>.{0,%d}?)", group, backreferenceLimit)
+				t.secondary.backreferences = append(t.secondary.backreferences, &backreference{group: group, ref: ref, placeholder: placeholder})
+				builder.WriteString(placeholder)
+				i++
+				continue
+			}
+			builder.WriteByte(c)
+			builder.WriteByte(next)
+			i++
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+			if c == '-' && (isClassEscapeAt(input, i-2) || isClassEscapeAt(input, i+1)) {
+				builder.WriteString("\\-")
+				continue
+			}
+			builder.WriteByte(c)
+		case c == '[':
+			inClass = true
+			builder.WriteByte(c)
+		case c == '(' && strings.HasPrefix(input[i:], "(?"):
+			negative, behind, prefixLen, ok := lookaroundPrefix(input[i:])
+			if !ok {
+				builder.WriteByte(c)
+				continue
+			}
+			end := closingParen(input, i)
+			if end == -1 {
+				builder.WriteByte(c)
+				continue
+			}
+			inner := input[i+prefixLen : end]
+			trailing := end == len(input)-1
+			i = end
+
+			switch {
+			case trailing && !negative && !behind:
+				// A trailing positive lookahead has the same matching
+				// behaviour as a non-capturing group.
+				builder.WriteString("(?:")
+				builder.WriteString(inner)
+				builder.WriteString(")")
+			case trailing && negative && !behind && isLiteral(inner):
+				builder.WriteString(negatedLiteral(inner))
+			default:
+				group := t.nextGroup()
+				t.secondary.assertions = append(t.secondary.assertions, &assertion{
+					group:    group,
+					pattern:  inner,
+					negative: negative,
+					behind:   behind,
+				})
+				fmt.Fprintf(&builder, "(?P<%s>)", group)
+			}
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return builder.String()
+}
+
+// lookaroundPrefix reports the type of lookaround at the start of s.
+func lookaroundPrefix(s string) (negative, behind bool, length int, ok bool) {
+	switch {
+	case strings.HasPrefix(s, "(?="):
+		return false, false, 3, true
+	case strings.HasPrefix(s, "(?!"):
+		return true, false, 3, true
+	case strings.HasPrefix(s, "(?<="):
+		return false, true, 4, true
+	case strings.HasPrefix(s, "(?<!"):
+		return true, true, 4, true
+	}
+	return false, false, 0, false
+}
+
+// closingParen returns the index of the parenthesis closing the
+// group opened at start, or -1 if it's unbalanced.
+func closingParen(s string, start int) int {
+	depth := 0
+	inClass := false
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isClassEscapeAt reports whether s has a class escape like \d at index i.
+func isClassEscapeAt(s string, i int) bool {
+	if i < 0 || i+1 >= len(s) || s[i] != '\\' {
+		return false
+	}
+	return strings.IndexByte("dDwWsS", s[i+1]) != -1
+}
+
+// isLiteral reports whether s only contains literal characters.
+func isLiteral(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' {
+			if i+1 >= len(s) || isAlphanumeric(s[i+1]) {
+				return false
+			}
+			i++
+			continue
+		}
+		if strings.IndexByte(`.[](){}*+?|^$`, c) != -1 {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// negatedLiteral returns a RE2 expression that matches wherever the
+// literal s does not follow, as is the case for a trailing (?!s).
+//
+// For example, "ab" becomes (?:$|[^a]|a(?:$|[^b])).
+func negatedLiteral(s string) string {
+	runes := []rune(unescapeLiteral(s))
+
+	var expression string
+	for i := len(runes) - 1; i >= 0; i-- {
+		r := runes[i]
+		if expression == "" {
+			expression = fmt.Sprintf("(?:$|[^%s])", classEscape(r))
+		} else {
+			expression = fmt.Sprintf("(?:$|[^%s]|%s%s)", classEscape(r), regexp.QuoteMeta(string(r)), expression)
+		}
+	}
+	return expression
+}
+
+// unescapeLiteral removes the escaping from a literal pattern.
+func unescapeLiteral(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		builder.WriteByte(s[i])
+	}
+	return builder.String()
+}
+
+// classEscape escapes a rune for use inside a character class.
+func classEscape(r rune) string {
+	if r < 128 && !isAlphanumeric(byte(r)) {
+		return `\` + string(r)
+	}
+	return string(r)
+}
+
+// compile compiles the lookarounds and resolves group names
+// against the compiled regex.
+func (m *secondaryMatcher) compile(regex *regexp.Regexp) error {
+	names := regex.SubexpNames()
+	for i, name := range names {
+		if i == 0 || strings.HasPrefix(name, internalGroupPrefix) {
+			continue
+		}
+		m.groups = append(m.groups, i)
+	}
+
+	for _, assertion := range m.assertions {
+		assertion.index = regex.SubexpIndex(assertion.group)
+
+		pattern, err := boundQuantifiers(assertion.pattern)
+		if err != nil {
+			return err
+		}
+		if assertion.behind {
+			pattern = "(?i)(?:" + pattern + ")$"
+		} else {
+			pattern = "(?i)^(?:" + pattern + ")"
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		assertion.regex = compiled
+	}
+	for _, backreference := range m.backreferences {
+		backreference.index = regex.SubexpIndex(backreference.group)
+	}
+	return nil
+}
+
+// match returns the submatches of the first candidate match for which
+// all lookarounds and backreferences hold. Submatches are numbered as
+// in the original pattern.
+func (m *secondaryMatcher) match(regex *regexp.Regexp, target string) []string {
+	for _, indexes := range regex.FindAllStringSubmatchIndex(target, maxSecondaryCandidates) {
+		if !m.valid(indexes, target) {
+			if indexes = m.resolve(regex, indexes, target); indexes == nil {
+				continue
+			}
+		}
+
+		submatches := make([]string, 0, len(m.groups)+1)
+		submatches = append(submatches, target[indexes[0]:indexes[1]])
+		for _, group := range m.groups {
+			submatches = append(submatches, submatchAt(indexes, group, target))
+		}
+		return submatches
+	}
+	return nil
+}
+
+func (m *secondaryMatcher) valid(indexes []int, target string) bool {
+	for _, assertion := range m.assertions {
+		position := indexes[2*assertion.index]
+		if position < 0 {
+			// The marker is in a branch that did not participate
+			continue
+		}
+
+		var matched bool
+		if assertion.behind {
+			start := position - lookbehindWindow
+			if start < 0 {
+				start = 0
+			}
+			matched = assertion.regex.MatchString(target[start:position])
+		} else {
+			matched = assertion.regex.MatchString(target[position:])
+		}
+		if matched == assertion.negative {
+			return false
+		}
+	}
+
+	for _, backreference := range m.backreferences {
+		position := indexes[2*backreference.index]
+		if position < 0 {
+			continue
+		}
+		// The placeholder group has to capture exactly the referenced
+		// text, as it matches any characters lazily.
+		var referenced string
+		if backreference.ref <= len(m.groups) {
+			referenced = submatchAt(indexes, m.groups[backreference.ref-1], target)
+		}
+		if indexes[2*backreference.index+1]-position != len(referenced) || !strings.EqualFold(target[position:position+len(referenced)], referenced) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolve retries a candidate rejected because of its backreferences with
+// the referenced texts in place of their placeholders, as a lazy placeholder
+// captures too little when nothing follows it, and returns the submatches
+// of the first valid match starting at or after the candidate if any.
+func (m *secondaryMatcher) resolve(regex *regexp.Regexp, indexes []int, target string) []int {
+	if len(m.backreferences) == 0 {
+		return nil
+	}
+
+	source := regex.String()
+	for _, backreference := range m.backreferences {
+		var referenced string
+		if backreference.ref <= len(m.groups) {
+			referenced = submatchAt(indexes, m.groups[backreference.ref-1], target)
+		}
+		literal := fmt.Sprintf("(?P<%s>(?i:%s))", backreference.group, regexp.QuoteMeta(referenced))
+		source = strings.Replace(source, backreference.placeholder, literal, 1)
+	}
+	resolved, err := regexp.Compile(source)
+	if err != nil {
+		return nil
+	}
+
+	for _, candidate := range resolved.FindAllStringSubmatchIndex(target, maxSecondaryCandidates) {
+		if candidate[0] >= indexes[0] && m.valid(candidate, target) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func submatchAt(indexes []int, group int, target string) string {
+	start, end := indexes[2*group], indexes[2*group+1]
+	if start < 0 {
+		return ""
+	}
+	return target[start:end]
+}
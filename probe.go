@@ -0,0 +1,226 @@
+package wappalyzer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProbeOptions contains the options for actively probing a site.
+type ProbeOptions struct {
+	// Client is the HTTP client used to send the probes
+	Client *http.Client
+	// BaseURL is the URL of the site the probed paths are resolved against
+	BaseURL string
+	// Budget is the maximum number of requests sent.
+	// Zero means DefaultProbeBudget.
+	Budget int
+	// Interval is the minimum delay between two requests
+	Interval time.Duration
+	// MaxSize is the maximum number of bytes read from a response.
+	// Zero means DefaultProbeMaxSize.
+	MaxSize int64
+}
+
+// default options for probing a site
+const (
+	DefaultProbeBudget  = 20
+	DefaultProbeMaxSize = 1024 * 1024
+)
+
+// probeResult contains the technologies confirmed by a probed path
+type probeResult struct {
+	url          string
+	technologies []matchPartResult
+}
+
+// Probe actively confirms candidate technologies on a site by requesting
+// the paths of their probe patterns and matching the responses. Candidates
+// are technology names, optionally with a version as returned by Fingerprint.
+//
+// Probes are only sent for candidates, in order, until the budget is spent,
+// in which case ErrProbeBudgetExhausted is returned. The confirmed
+// technologies are returned even if an error occurs.
+func (s *Wappalyze) Probe(ctx context.Context, options ProbeOptions, candidates []string) (map[string]struct{}, error) {
+	uniqueFingerprints := NewUniqueFingerprints()
+
+	results, err := s.probe(ctx, options, candidates)
+	for _, result := range results {
+		addResults(uniqueFingerprints, result.technologies)
+	}
+	return uniqueFingerprints.GetValues(), err
+}
+
+// Probe actively confirms the technologies of the session detected with
+// less than full confidence, adding the responses as evidence.
+func (s *Session) Probe(ctx context.Context, options ProbeOptions) error {
+	s.mutex.Lock()
+	var candidates []string
+	for app, metadata := range s.fingerprints.values {
+		if metadata.confidence < 100 {
+			candidates = append(candidates, app)
+		}
+	}
+	s.mutex.Unlock()
+	sort.Strings(candidates)
+
+	results, err := s.wappalyzer.probe(ctx, options, candidates)
+	for _, result := range results {
+		s.add(SourceProbe, result.url, newResults(result.technologies))
+	}
+	return err
+}
+
+// probe sends the probes of candidates and returns the confirmed technologies
+func (s *Wappalyze) probe(ctx context.Context, options ProbeOptions, candidates []string) ([]probeResult, error) {
+	if options.Client == nil {
+		return nil, errors.New("no client provided for probing")
+	}
+	base, err := url.Parse(options.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, errors.New("invalid base url for probing: " + options.BaseURL)
+	}
+	if options.Budget <= 0 {
+		options.Budget = DefaultProbeBudget
+	}
+	if options.MaxSize <= 0 {
+		options.MaxSize = DefaultProbeMaxSize
+	}
+
+	prober := &prober{
+		options:   options,
+		base:      base,
+		responses: make(map[string]*probeResponse),
+	}
+
+	var results []probeResult
+	for _, candidate := range candidates {
+		name, _, _ := strings.Cut(candidate, versionSeparator)
+		fingerprint, ok := s.fingerprints.Apps[name]
+		if !ok || len(fingerprint.probe) == 0 {
+			continue
+		}
+
+		paths := make([]string, 0, len(fingerprint.probe))
+		for path := range fingerprint.probe {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			response, err := prober.get(ctx, path)
+			if err != nil {
+				return results, err
+			}
+			if response == nil {
+				continue
+			}
+
+			pattern := fingerprint.probe[path]
+			valid, version := pattern.Evaluate(response.body)
+			if !valid {
+				continue
+			}
+			technologies := []matchPartResult{{
+				application: name,
+				version:     version,
+				confidence:  pattern.Confidence,
+			}}
+			for _, implies := range fingerprint.implies {
+				technologies = append(technologies, matchPartResult{
+					application: implies,
+					confidence:  pattern.Confidence,
+				})
+			}
+			results = append(results, probeResult{url: response.url, technologies: technologies})
+		}
+	}
+	return results, nil
+}
+
+// prober sends rate limited probes within a budget, sending
+// a single request for paths shared by several technologies.
+type prober struct {
+	options ProbeOptions
+	base    *url.URL
+	// responses is organized as <path, response>, nil for failed probes
+	responses map[string]*probeResponse
+	sent      int
+	last      time.Time
+}
+
+type probeResponse struct {
+	url  string
+	body string
+}
+
+// ErrProbeBudgetExhausted is returned when probing stopped
+// because the budget was spent.
+var ErrProbeBudgetExhausted = errors.New("probe budget exhausted")
+
+// get returns the response of a successful probe of path,
+// or nil if the request failed or the status was not 2xx.
+func (p *prober) get(ctx context.Context, path string) (*probeResponse, error) {
+	if response, ok := p.responses[path]; ok {
+		return response, nil
+	}
+	if p.sent >= p.options.Budget {
+		return nil, ErrProbeBudgetExhausted
+	}
+	target, err := p.base.Parse(path)
+	if err != nil {
+		p.responses[path] = nil
+		return nil, nil
+	}
+
+	if wait := p.options.Interval - time.Since(p.last); p.sent > 0 && wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	p.sent++
+	p.last = time.Now()
+
+	response, err := p.request(ctx, target.String())
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		response = nil
+	}
+	p.responses[path] = response
+	return response, nil
+}
+
+func (p *prober) request(ctx context.Context, target string) (*probeResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.options.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, p.options.MaxSize))
+	if err != nil {
+		return nil, err
+	}
+	return &probeResponse{url: target, body: string(body)}, nil
+}
@@ -0,0 +1,95 @@
+package wappalyzer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"WordPress": {
+			"url": ["/wp-content/\\;confidence:50"],
+			"probe": {"/wp-login.php": "", "/readme.html": "Version ([\\d.]+)\\;version:\\1"},
+			"implies": ["PHP"]
+		},
+		"Joomla": {"probe": {"/administrator/": "Joomla"}},
+		"Drupal": {"probe": {"/core/CHANGELOG.txt": "Drupal"}}
+	}`)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/wp-login.php":
+			_, _ = fmt.Fprint(w, "login")
+		case "/readme.html":
+			_, _ = fmt.Fprint(w, "<h1>WordPress</h1> Version 6.4.2")
+		case "/administrator/":
+			_, _ = fmt.Fprint(w, "Administration")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	options := ProbeOptions{Client: server.Client(), BaseURL: server.URL + "/blog/"}
+
+	matches, err := wappalyzer.Probe(context.Background(), options, []string{"WordPress:6.4", "Joomla", "Drupal", "Unknown"})
+	require.NoError(t, err, "could not probe")
+	require.Equal(t, map[string]struct{}{"WordPress:6.4.2": {}, "PHP": {}}, matches, "could not get probe matches")
+	require.Equal(t, int32(4), requests.Load(), "could not get correct number of requests")
+
+	t.Run("budget", func(t *testing.T) {
+		options := options
+		options.Budget = 1
+
+		matches, err := wappalyzer.Probe(context.Background(), options, []string{"Joomla", "WordPress"})
+		require.ErrorIs(t, err, ErrProbeBudgetExhausted, "could probe above budget")
+		require.Empty(t, matches, "could get matches above budget")
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		options := options
+		options.Interval = 50 * time.Millisecond
+
+		start := time.Now()
+		_, err := wappalyzer.Probe(context.Background(), options, []string{"WordPress"})
+		require.NoError(t, err, "could not probe")
+		require.GreaterOrEqual(t, time.Since(start), options.Interval, "could probe without rate limiting")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := wappalyzer.Probe(context.Background(), ProbeOptions{BaseURL: server.URL}, []string{"WordPress"})
+		require.Error(t, err, "could probe without client")
+
+		_, err = wappalyzer.Probe(context.Background(), ProbeOptions{Client: server.Client(), BaseURL: "example.com"}, []string{"WordPress"})
+		require.Error(t, err, "could probe without scheme")
+	})
+
+	t.Run("session", func(t *testing.T) {
+		session := wappalyzer.NewSession(server.URL)
+		session.AddPage(server.URL+"/wp-content/themes/", map[string][]string{}, []byte(""))
+		require.Equal(t, 50, session.Profile().Technologies["WordPress"].Confidence)
+
+		err := session.Probe(context.Background(), ProbeOptions{Client: server.Client(), BaseURL: server.URL})
+		require.NoError(t, err, "could not probe session")
+
+		technology := session.Profile().Technologies["WordPress"]
+		require.Equal(t, 100, technology.Confidence, "could not confirm technology")
+		require.Equal(t, "6.4.2", technology.Version, "could not get probed version")
+
+		var sources []string
+		for _, evidence := range technology.Evidence {
+			sources = append(sources, string(evidence.Source)+" "+strings.TrimPrefix(evidence.URL, server.URL))
+		}
+		require.Equal(t, []string{"page /wp-content/themes/", "probe /readme.html", "probe /wp-login.php"}, sources)
+	})
+}
@@ -0,0 +1,172 @@
+package wappalyzer
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// RetireRepository is a retire.js vulnerability repository, as found in
+// its jsrepository.json file, used to flag vulnerable javascript
+// libraries among the detected technologies.
+type RetireRepository struct {
+	// components is organized as <component name, component>
+	components map[string]*retireComponent
+	// aliases maps technology names to component names
+	aliases map[string]string
+}
+
+// RetireVulnerability is a vulnerable version range of a javascript library.
+type RetireVulnerability struct {
+	// Component is the retire.js name of the library
+	Component string
+	// Version is the version of the library that was checked
+	Version string
+	// AtOrAbove is the first vulnerable version, if there's any
+	AtOrAbove string
+	// Below is the first version that is not vulnerable anymore
+	Below    string
+	Severity string
+	// Identifiers contains the CVE, GHSA, bug and issue identifiers
+	// as well as a summary of the vulnerability.
+	Identifiers map[string][]string
+	Info        []string
+	CWE         []string
+}
+
+type retireComponent struct {
+	Vulnerabilities []retireVulnerability `json:"vulnerabilities"`
+}
+
+type retireVulnerability struct {
+	Below       string                     `json:"below"`
+	AtOrAbove   string                     `json:"atOrAbove"`
+	Severity    string                     `json:"severity"`
+	Identifiers map[string]json.RawMessage `json:"identifiers"`
+	Info        []string                   `json:"info"`
+	CWE         []string                   `json:"cwe"`
+}
+
+// retireAliases maps technology names from the fingerprints to
+// their retire.js component names when they differ.
+var retireAliases = map[string]string{
+	"Ember.js":    "ember",
+	"Knockout.js": "knockout",
+	"Prototype":   "prototypejs",
+	"Vue.js":      "vue",
+	"YUI":         "YUI",
+}
+
+// LoadRetireRepository loads a retire.js repository from a jsrepository.json file.
+func LoadRetireRepository(filePath string) (*RetireRepository, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewRetireRepository(f)
+}
+
+// NewRetireRepository creates a retire.js repository from the
+// contents of a jsrepository.json file.
+func NewRetireRepository(reader io.Reader) (*RetireRepository, error) {
+	var components map[string]*retireComponent
+	if err := json.NewDecoder(reader).Decode(&components); err != nil {
+		return nil, err
+	}
+
+	repository := &RetireRepository{
+		components: components,
+		aliases:    make(map[string]string, len(retireAliases)),
+	}
+	for technology, component := range retireAliases {
+		repository.aliases[technology] = component
+	}
+	return repository, nil
+}
+
+// SetAlias sets the retire.js component name used for a technology.
+func (r *RetireRepository) SetAlias(technology, component string) {
+	r.aliases[technology] = component
+}
+
+// componentName returns the component name for a technology, falling back
+// to the lowercased name with spaces replaced by dashes.
+func (r *RetireRepository) componentName(technology string) string {
+	if component, ok := r.aliases[technology]; ok {
+		return component
+	}
+	return strings.ReplaceAll(strings.ToLower(technology), " ", "-")
+}
+
+// Check returns the vulnerabilities of a retire.js component
+// whose range contains version.
+func (r *RetireRepository) Check(component, version string) []RetireVulnerability {
+	item, ok := r.components[component]
+	if !ok || version == "" {
+		return nil
+	}
+
+	var vulnerabilities []RetireVulnerability
+	for _, vulnerability := range item.Vulnerabilities {
+		if vulnerability.AtOrAbove != "" && CompareVersions(version, vulnerability.AtOrAbove) < 0 {
+			continue
+		}
+		if vulnerability.Below != "" && CompareVersions(version, vulnerability.Below) >= 0 {
+			continue
+		}
+
+		vulnerabilities = append(vulnerabilities, RetireVulnerability{
+			Component:   component,
+			Version:     version,
+			AtOrAbove:   vulnerability.AtOrAbove,
+			Below:       vulnerability.Below,
+			Severity:    vulnerability.Severity,
+			Identifiers: normalizeRetireIdentifiers(vulnerability.Identifiers),
+			Info:        vulnerability.Info,
+			CWE:         vulnerability.CWE,
+		})
+	}
+	return vulnerabilities
+}
+
+// CheckFingerprints returns the vulnerabilities of the technologies
+// detected by a Fingerprint call, keyed like the detections.
+// Technologies without a version are skipped.
+func (r *RetireRepository) CheckFingerprints(apps map[string]struct{}) map[string][]RetireVulnerability {
+	result := make(map[string][]RetireVulnerability)
+	for app := range apps {
+		name, version, _ := strings.Cut(app, versionSeparator)
+		if version == "" {
+			continue
+		}
+		if vulnerabilities := r.Check(r.componentName(name), version); len(vulnerabilities) > 0 {
+			result[app] = vulnerabilities
+		}
+	}
+	return result
+}
+
+// normalizeRetireIdentifiers converts identifiers, which are either
+// strings or lists of strings, into lists of strings.
+func normalizeRetireIdentifiers(identifiers map[string]json.RawMessage) map[string][]string {
+	normalized := make(map[string][]string, len(identifiers))
+	for key, value := range identifiers {
+		var list []string
+		if err := json.Unmarshal(value, &list); err == nil {
+			normalized[key] = list
+			continue
+		}
+		var single string
+		if err := json.Unmarshal(value, &single); err == nil {
+			normalized[key] = []string{single}
+		}
+	}
+	for _, values := range normalized {
+		sort.Strings(values)
+	}
+	return normalized
+}
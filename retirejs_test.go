@@ -0,0 +1,101 @@
+package wappalyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testRetireRepository = `{
+	"jquery": {
+		"vulnerabilities": [
+			{
+				"below": "1.9.0b1",
+				"atOrAbove": "1.7.1",
+				"severity": "medium",
+				"identifiers": {
+					"CVE": ["CVE-2012-6708"],
+					"bug": "11290",
+					"summary": "Selector interpreted as HTML"
+				},
+				"info": ["https://bugs.jquery.com/ticket/11290"],
+				"cwe": ["CWE-79"]
+			},
+			{
+				"below": "3.5.0",
+				"atOrAbove": "1.2.0",
+				"severity": "medium",
+				"identifiers": {
+					"CVE": ["CVE-2020-11022"],
+					"githubID": "GHSA-gxr4-xjj5-5px2"
+				},
+				"info": ["https://blog.jquery.com/2020/04/10/jquery-3-5-0-released/"]
+			}
+		],
+		"extractors": {
+			"uri": ["/(§§version§§)/jquery(\\.min)?\\.js"]
+		}
+	},
+	"moment.js": {
+		"vulnerabilities": [
+			{"below": "2.29.4", "severity": "high", "identifiers": {"CVE": ["CVE-2022-31129"]}}
+		]
+	}
+}`
+
+func TestRetireRepository(t *testing.T) {
+	repository, err := NewRetireRepository(strings.NewReader(testRetireRepository))
+	require.NoError(t, err, "could not load retire.js repository")
+
+	t.Run("check", func(t *testing.T) {
+		vulnerabilities := repository.Check("jquery", "1.8.3")
+		require.Len(t, vulnerabilities, 2)
+		require.Equal(t, "1.7.1", vulnerabilities[0].AtOrAbove)
+		require.Equal(t, "1.9.0b1", vulnerabilities[0].Below)
+		require.Equal(t, "medium", vulnerabilities[0].Severity)
+		require.Equal(t, []string{"CVE-2012-6708"}, vulnerabilities[0].Identifiers["CVE"])
+		require.Equal(t, []string{"Selector interpreted as HTML"}, vulnerabilities[0].Identifiers["summary"])
+		require.Equal(t, []string{"GHSA-gxr4-xjj5-5px2"}, vulnerabilities[1].Identifiers["githubID"])
+
+		vulnerabilities = repository.Check("jquery", "1.9.0")
+		require.Len(t, vulnerabilities, 1, "could check release against its pre-release bound")
+		require.Equal(t, "3.5.0", vulnerabilities[0].Below)
+		require.Len(t, repository.Check("jquery", "1.9.0a1"), 2, "could not check pre-release below its bound")
+
+		require.Len(t, repository.Check("jquery", "3.4.1"), 1)
+		require.Empty(t, repository.Check("jquery", "3.5.0"))
+		require.Empty(t, repository.Check("jquery", "1.1"))
+		require.Len(t, repository.Check("moment.js", "2.0.0"), 1, "could not check range without lower bound")
+		require.Empty(t, repository.Check("unknown", "1.0"))
+	})
+
+	t.Run("fingerprints", func(t *testing.T) {
+		vulnerabilities := repository.CheckFingerprints(map[string]struct{}{
+			"jQuery:3.4.1":     {},
+			"Moment.js:2.29.4": {},
+			"jQuery UI":        {},
+			"Nginx:1.25.3":     {},
+		})
+		require.Len(t, vulnerabilities, 1)
+		require.Len(t, vulnerabilities["jQuery:3.4.1"], 1)
+		require.Equal(t, "jquery", vulnerabilities["jQuery:3.4.1"][0].Component)
+	})
+
+	t.Run("alias", func(t *testing.T) {
+		repository.SetAlias("Moment", "moment.js")
+		vulnerabilities := repository.CheckFingerprints(map[string]struct{}{"Moment:2.10.0": {}})
+		require.Len(t, vulnerabilities["Moment:2.10.0"], 1)
+	})
+}
+
+func TestLoadRetireRepository(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "jsrepository.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(testRetireRepository), 0o600))
+
+	repository, err := LoadRetireRepository(filePath)
+	require.NoError(t, err, "could not load retire.js repository")
+	require.Len(t, repository.Check("jquery", "2.2.4"), 1)
+}
@@ -0,0 +1,225 @@
+package wappalyzer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sort"
+	"sync"
+)
+
+// Session aggregates the technologies detected across many responses of
+// a site, such as its pages, redirects, robots.txt file and assets, as a
+// single page usually only shows part of the stack.
+//
+// Evidence is accumulated with the same confidence rules as a single
+// Fingerprint call, keeping the most specific version seen. It is safe
+// for concurrent use.
+type Session struct {
+	wappalyzer *Wappalyze
+	origin     string
+
+	mutex        sync.Mutex
+	fingerprints UniqueFingerprints
+	// evidence is organized as <technology name, evidence>
+	evidence map[string][]Evidence
+}
+
+// EvidenceSource is the kind of input a technology was detected from.
+type EvidenceSource string
+
+// sources of evidence for a technology
+const (
+	SourcePage        EvidenceSource = "page"
+	SourceRedirect    EvidenceSource = "redirect"
+	SourceRobots      EvidenceSource = "robots"
+	SourceScript      EvidenceSource = "script"
+	SourceStylesheet  EvidenceSource = "stylesheet"
+	SourceFavicon     EvidenceSource = "favicon"
+	SourceDNS         EvidenceSource = "dns"
+	SourceCertificate EvidenceSource = "certificate"
+	SourceProbe       EvidenceSource = "probe"
+)
+
+// Evidence is a detection of a technology from a single input.
+type Evidence struct {
+	Source EvidenceSource
+	// URL is the URL of the input, if there's any
+	URL        string
+	Version    string
+	Confidence int
+}
+
+// SiteProfile is the consolidated technology profile of a site.
+type SiteProfile struct {
+	Origin string
+	// Technologies is organized as <name, technology>
+	Technologies map[string]*SiteTechnology
+}
+
+// SiteTechnology is a technology detected on a site,
+// along with the evidence it was detected from.
+type SiteTechnology struct {
+	Name       string
+	Version    string
+	Confidence int
+	Evidence   []Evidence
+}
+
+// NewSession creates a new session for aggregating the
+// technologies detected on the site at origin.
+func (s *Wappalyze) NewSession(origin string) *Session {
+	return &Session{
+		wappalyzer:   s,
+		origin:       origin,
+		fingerprints: NewUniqueFingerprints(),
+		evidence:     make(map[string][]Evidence),
+	}
+}
+
+// AddPage adds a page of the site, matching its URL, headers and body.
+//
+// Body should not be mutated while this function is being called, or it may
+// lead to unexpected things.
+func (s *Session) AddPage(targetURL string, headers map[string][]string, body []byte) {
+	uniqueFingerprints, _ := s.wappalyzer.fingerprint(headers, body)
+	addResults(uniqueFingerprints, s.wappalyzer.checkURL(targetURL))
+
+	s.add(SourcePage, targetURL, uniqueFingerprints)
+}
+
+// AddRedirect adds a redirect response of the site,
+// matching its URL and headers.
+func (s *Session) AddRedirect(targetURL string, headers map[string][]string) {
+	uniqueFingerprints, _ := s.wappalyzer.fingerprint(headers, nil)
+	addResults(uniqueFingerprints, s.wappalyzer.checkURL(targetURL))
+
+	s.add(SourceRedirect, targetURL, uniqueFingerprints)
+}
+
+// AddRobots adds the robots.txt file of the site.
+func (s *Session) AddRobots(targetURL string, body []byte) {
+	s.add(SourceRobots, targetURL, newResults(s.wappalyzer.checkRobots(body)))
+}
+
+// AddScript adds a script of the site, matching its URL against the
+// scriptSrc patterns and its contents against the scripts patterns and
+// the hash database.
+func (s *Session) AddScript(targetURL string, body []byte) {
+	uniqueFingerprints := NewUniqueFingerprints()
+	if targetURL != "" {
+		addResults(uniqueFingerprints, s.wappalyzer.fingerprints.matchString(targetURL, scriptPart))
+	}
+	addResults(uniqueFingerprints, s.wappalyzer.checkScriptContent(body))
+
+	s.add(SourceScript, targetURL, uniqueFingerprints)
+}
+
+// AddStylesheet adds a stylesheet of the site, matching its contents
+// against the css patterns and the hash database.
+func (s *Session) AddStylesheet(targetURL string, body []byte) {
+	uniqueFingerprints := newResults(s.wappalyzer.checkCSS(body))
+	addResults(uniqueFingerprints, s.wappalyzer.checkContentHash(body))
+
+	s.add(SourceStylesheet, targetURL, uniqueFingerprints)
+}
+
+// AddFavicon adds a favicon of the site, matching it against the favicon database.
+func (s *Session) AddFavicon(targetURL string, body []byte) {
+	s.add(SourceFavicon, targetURL, newResults(s.wappalyzer.checkFavicons([][]byte{body})))
+}
+
+// AddDNS adds the DNS records of the site, as accepted by FingerprintDNS.
+func (s *Session) AddDNS(records map[string][]string) {
+	s.add(SourceDNS, "", newResults(s.wappalyzer.checkDNS(records)))
+}
+
+// AddCertificates adds the TLS certificate chain of the site.
+func (s *Session) AddCertificates(certificates []*x509.Certificate) {
+	s.add(SourceCertificate, "", newResults(s.wappalyzer.checkCertificates(certificates)))
+}
+
+// AddTLS adds the TLS connection state of a response of the site.
+func (s *Session) AddTLS(state *tls.ConnectionState) {
+	if state == nil {
+		return
+	}
+	s.AddCertificates(state.PeerCertificates)
+}
+
+// add merges the technologies detected from a single input into the session
+func (s *Session) add(source EvidenceSource, targetURL string, uniqueFingerprints UniqueFingerprints) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for app, metadata := range uniqueFingerprints.values {
+		if metadata.confidence == 0 {
+			continue
+		}
+		s.fingerprints.setMoreSpecific(app, metadata.version, metadata.confidence)
+		s.evidence[app] = append(s.evidence[app], Evidence{
+			Source:     source,
+			URL:        targetURL,
+			Version:    metadata.version,
+			Confidence: metadata.confidence,
+		})
+	}
+}
+
+// Fingerprints returns the technologies detected so far,
+// in the same format as Fingerprint.
+func (s *Session) Fingerprints() map[string]struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.fingerprints.GetValues()
+}
+
+// Profile returns the consolidated technology profile of the site.
+func (s *Session) Profile() *SiteProfile {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	profile := &SiteProfile{
+		Origin:       s.origin,
+		Technologies: make(map[string]*SiteTechnology, len(s.fingerprints.values)),
+	}
+	for app, metadata := range s.fingerprints.values {
+		if metadata.confidence == 0 {
+			continue
+		}
+		evidence := make([]Evidence, len(s.evidence[app]))
+		copy(evidence, s.evidence[app])
+
+		profile.Technologies[app] = &SiteTechnology{
+			Name:       app,
+			Version:    metadata.version,
+			Confidence: metadata.confidence,
+			Evidence:   evidence,
+		}
+	}
+	return profile
+}
+
+// Names returns the sorted names of the technologies of the profile.
+func (p *SiteProfile) Names() []string {
+	names := make([]string, 0, len(p.Technologies))
+	for name := range p.Technologies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newResults creates unique fingerprints from match results
+func newResults(results []matchPartResult) UniqueFingerprints {
+	uniqueFingerprints := NewUniqueFingerprints()
+	addResults(uniqueFingerprints, results)
+	return uniqueFingerprints
+}
+
+// addResults adds match results to unique fingerprints
+func addResults(uniqueFingerprints UniqueFingerprints, results []matchPartResult) {
+	for _, app := range results {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+}
@@ -0,0 +1,69 @@
+package wappalyzer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"WordPress": {
+			"meta": {"generator": ["^WordPress ?([\\d.]+)?\\;version:\\1"]},
+			"robots": ["Disallow: /wp-admin/\\;confidence:50"],
+			"url": ["/wp-content/\\;confidence:50"],
+			"implies": ["PHP"]
+		},
+		"jQuery": {"scriptSrc": ["jquery[.-]([\\d.]*\\d)[^/]*\\.js\\;version:\\1"]},
+		"React": {"scripts": ["react\\.production\\.min\\.js"]},
+		"Nginx": {"headers": {"server": "nginx(?:/([\\d.]+))?\\;version:\\1"}}
+	}`)
+
+	session := wappalyzer.NewSession("https://example.com")
+	session.AddRedirect("http://example.com/", map[string][]string{"Server": {"nginx"}})
+	session.AddPage("https://example.com/", map[string][]string{"Server": {"nginx/1.25.3"}}, []byte(`<meta name="generator" content="WordPress 6.4">`))
+	session.AddPage("https://example.com/about/", map[string][]string{}, []byte(`<meta name="generator" content="WordPress 6.4.2">`))
+	session.AddScript("https://example.com/wp-includes/js/jquery/jquery-3.7.1.min.js", nil)
+	session.AddScript("https://example.com/assets/app.js", []byte("/** @license react.production.min.js */"))
+
+	require.Equal(t, map[string]struct{}{
+		"WordPress:6.4.2": {},
+		"PHP":             {},
+		"jQuery:3.7.1":    {},
+		"Nginx:1.25.3":    {},
+		"React":           {},
+	}, session.Fingerprints(), "could not get most specific versions")
+
+	profile := session.Profile()
+	require.Equal(t, "https://example.com", profile.Origin)
+	require.Equal(t, []string{"Nginx", "PHP", "React", "WordPress", "jQuery"}, profile.Names())
+
+	nginx := profile.Technologies["Nginx"]
+	require.Equal(t, []Evidence{
+		{Source: SourceRedirect, URL: "http://example.com/", Confidence: 100},
+		{Source: SourcePage, URL: "https://example.com/", Version: "1.25.3", Confidence: 100},
+	}, nginx.Evidence, "could not get evidence")
+
+	t.Run("confidence", func(t *testing.T) {
+		session := wappalyzer.NewSession("https://blog.example.com")
+		session.AddRobots("https://blog.example.com/robots.txt", []byte("User-agent: *\nDisallow: /wp-admin/\n"))
+
+		technology := session.Profile().Technologies["WordPress"]
+		require.Equal(t, 50, technology.Confidence, "could not get partial confidence")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				session.AddPage("https://blog.example.com/wp-content/uploads/", map[string][]string{}, nil)
+			}()
+		}
+		wg.Wait()
+
+		technology = session.Profile().Technologies["WordPress"]
+		require.Equal(t, 100, technology.Confidence, "could not accumulate confidence")
+		require.Len(t, technology.Evidence, 5, "could not get all evidence")
+	})
+}
@@ -1,7 +1,6 @@
 package wappalyzer
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +11,15 @@ import (
 type Wappalyze struct {
 	original     *Fingerprints
 	fingerprints *CompiledFingerprints
+	// hashes contains known library files, if set
+	hashes *HashDatabase
+	// favicons contains known favicons, if set
+	favicons *FaviconDatabase
+	// fetcher retrieves external scripts and stylesheets, if set
+	fetcher *fetcher
+	// hasText indicates whether any fingerprint has text patterns,
+	// as building the visible text of pages is only needed for them
+	hasText bool
 }
 
 // New creates a new tech detection instance
@@ -71,6 +79,7 @@ func (s *Wappalyze) loadFingerprints() error {
 	for i, fingerprint := range fingerprintsStruct.Apps {
 		s.fingerprints.Apps[i] = compileFingerprint(fingerprint)
 	}
+	s.hasText = s.fingerprints.hasText()
 	return nil
 }
 
@@ -116,6 +125,7 @@ func (s *Wappalyze) loadFingerprintsFromFile(filePath string, loadEmbedded, supe
 	for i, fingerprint := range s.original.Apps {
 		s.fingerprints.Apps[i] = compileFingerprint(fingerprint)
 	}
+	s.hasText = s.fingerprints.hasText()
 
 	return nil
 }
@@ -123,13 +133,25 @@ func (s *Wappalyze) loadFingerprintsFromFile(filePath string, loadEmbedded, supe
 // Fingerprint identifies technologies on a target,
 // based on the received response headers and body.
 //
+// The body is checked according to its content type, sniffed from the body
+// if the content-type header is absent: HTML is matched against the html
+// patterns, JavaScript against the scripts patterns and CSS against the css
+// patterns, while JSON, XML and binary bodies are skipped.
+//
 // Body should not be mutated while this function is being called, or it may
 // lead to unexpected things.
 func (s *Wappalyze) Fingerprint(headers map[string][]string, body []byte) map[string]struct{} {
+	uniqueFingerprints, _ := s.fingerprint(headers, body)
+	return uniqueFingerprints.GetValues()
+}
+
+// fingerprint runs the header, cookie and body fingerprinting and returns
+// the detected technologies along with the information collected from the body.
+func (s *Wappalyze) fingerprint(headers map[string][]string, body []byte) (UniqueFingerprints, bodyInfo) {
 	uniqueFingerprints := NewUniqueFingerprints()
 
-	// Lowercase everything that we have received to check
-	normalizedBody := bytes.ToLower(body)
+	// Patterns are case-insensitive, only header names need to be lowercased
+	// so that versions keep the case they were sent with.
 	normalizedHeaders := s.normalizeHeaders(headers)
 
 	// Run header based fingerprinting if the number
@@ -138,7 +160,7 @@ func (s *Wappalyze) Fingerprint(headers map[string][]string, body []byte) map[st
 		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
 	}
 
-	cookies := s.findSetCookie(normalizedHeaders)
+	cookies := s.findSetCookie(headers)
 	// Run cookie based fingerprinting if we have a set-cookie header
 	if len(cookies) > 0 {
 		for _, app := range s.checkCookies(cookies) {
@@ -147,11 +169,11 @@ func (s *Wappalyze) Fingerprint(headers map[string][]string, body []byte) map[st
 	}
 
 	// Check for stuff in the body finally
-	bodyTech := s.checkBody(normalizedBody)
+	bodyTech, info, _ := s.checkContent(normalizedHeaders["content-type"], body)
 	for _, app := range bodyTech {
 		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
 	}
-	return uniqueFingerprints.GetValues()
+	return uniqueFingerprints, info
 }
 
 type UniqueFingerprints struct {
@@ -203,6 +225,18 @@ func (u UniqueFingerprints) SetIfNotExists(value, version string, confidence int
 	}
 }
 
+// setMoreSpecific is like SetIfNotExists but replaces the version
+// if the new one is more specific.
+func (u UniqueFingerprints) setMoreSpecific(value, version string, confidence int) {
+	existing, ok := u.values[value]
+	u.SetIfNotExists(value, version, confidence)
+	if ok && version != "" && existing.version != "" && isMoreSpecific(version, existing.version) {
+		updated := u.values[value]
+		updated.version = version
+		u.values[value] = updated
+	}
+}
+
 type matchPartResult struct {
 	application string
 	confidence  int
@@ -211,15 +245,15 @@ type matchPartResult struct {
 
 // FingerprintWithTitle identifies technologies on a target,
 // based on the received response headers and body.
-// It also returns the title of the page.
+// It also returns the title of the page, if the body is HTML.
 //
 // Body should not be mutated while this function is being called, or it may
 // lead to unexpected things.
 func (s *Wappalyze) FingerprintWithTitle(headers map[string][]string, body []byte) (map[string]struct{}, string) {
 	uniqueFingerprints := NewUniqueFingerprints()
 
-	// Lowercase everything that we have received to check
-	normalizedBody := bytes.ToLower(body)
+	// Patterns are case-insensitive, only header names need to be lowercased
+	// so that versions keep the case they were sent with.
 	normalizedHeaders := s.normalizeHeaders(headers)
 
 	// Run header based fingerprinting if the number
@@ -228,7 +262,7 @@ func (s *Wappalyze) FingerprintWithTitle(headers map[string][]string, body []byt
 		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
 	}
 
-	cookies := s.findSetCookie(normalizedHeaders)
+	cookies := s.findSetCookie(headers)
 	// Run cookie based fingerprinting if we have a set-cookie header
 	if len(cookies) > 0 {
 		for _, app := range s.checkCookies(cookies) {
@@ -237,15 +271,14 @@ func (s *Wappalyze) FingerprintWithTitle(headers map[string][]string, body []byt
 	}
 
 	// Check for stuff in the body finally
-	if strings.Contains(normalizedHeaders["content-type"], "text/html") {
-		bodyTech := s.checkBody(normalizedBody)
-		for _, app := range bodyTech {
-			uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
-		}
-		title := s.getTitle(body)
-		return uniqueFingerprints.GetValues(), title
+	bodyTech, _, kind := s.checkContent(normalizedHeaders["content-type"], body)
+	for _, app := range bodyTech {
+		uniqueFingerprints.SetIfNotExists(app.application, app.version, app.confidence)
+	}
+	if kind != htmlContent {
+		return uniqueFingerprints.GetValues(), ""
 	}
-	return uniqueFingerprints.GetValues(), ""
+	return uniqueFingerprints.GetValues(), s.getTitle(body)
 }
 
 // FingerprintWithInfo identifies technologies on a target,
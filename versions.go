@@ -1,6 +1,7 @@
 package wappalyzer
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -19,6 +20,12 @@ func isMoreSpecific(a, b string) bool {
 
 // versionLess reports whether version a < version b.
 func versionLess(a, b string) bool {
+	return CompareVersions(a, b) < 0
+}
+
+// versionLessNumeric reports whether version a < version b comparing
+// only the numeric value of their dot separated parts.
+func versionLessNumeric(a, b string) bool {
 	aParts := splitParts(a)
 	bParts := splitParts(b)
 
@@ -59,3 +66,398 @@ func splitParts(v string) []int {
 	}
 	return parts
 }
+
+// Version is a version string as extracted by the fingerprints.
+//
+// It understands the shapes found in the wild, such as "v1.2.3",
+// "1.0.2k", "2.0.0-rc.1", "3.1beta2" and "1.4.0+build.5".
+//
+// A bare letter ending the version is a suffix ordering after the release,
+// as in OpenSSL releases like "1.0.2k", while a letter followed by a number
+// is a pre-release tag, "a", "b" and "c" standing for alpha, beta and rc
+// as in "1.9.0b1". So "3.1" < "3.1a" but "3.1a1" < "3.1".
+type Version struct {
+	// Original is the version string that was parsed
+	Original string
+	// Segments contains the numeric release segments
+	Segments []int
+	// Suffix is a letter suffix directly following the release
+	// segments, as used by OpenSSL releases like "1.0.2k".
+	Suffix string
+	// Prerelease is the pre-release tag, like "rc.1" or "beta2"
+	Prerelease string
+	// Build is the build metadata following a '+'
+	Build string
+}
+
+// prereleaseRanks orders the well known pre-release tags,
+// unknown tags rank below all of them.
+var prereleaseRanks = map[string]int{
+	"dev":       1,
+	"snapshot":  1,
+	"a":         2,
+	"alpha":     2,
+	"b":         3,
+	"beta":      3,
+	"m":         4,
+	"milestone": 4,
+	"pre":       5,
+	"preview":   5,
+	"c":         6,
+	"rc":        6,
+}
+
+// ParseVersion parses a version string.
+func ParseVersion(v string) (*Version, error) {
+	version := &Version{Original: v}
+
+	remaining := strings.TrimSpace(v)
+	if len(remaining) > 0 && (remaining[0] == 'v' || remaining[0] == 'V') {
+		remaining = remaining[1:]
+	}
+	if index := strings.IndexByte(remaining, '+'); index != -1 {
+		version.Build = remaining[index+1:]
+		remaining = remaining[:index]
+	}
+	if remaining == "" || !isDigit(remaining[0]) {
+		return nil, fmt.Errorf("invalid version: %q", v)
+	}
+
+	// Release segments are dot separated numbers
+	for {
+		end := 0
+		for end < len(remaining) && isDigit(remaining[end]) {
+			end++
+		}
+		number, err := strconv.Atoi(remaining[:end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version: %q", v)
+		}
+		version.Segments = append(version.Segments, number)
+		remaining = remaining[end:]
+
+		if len(remaining) < 2 || remaining[0] != '.' || !isDigit(remaining[1]) {
+			break
+		}
+		remaining = remaining[1:]
+	}
+
+	// A single letter that's not followed by anything else is a suffix,
+	// everything else is a pre-release tag.
+	if len(remaining) == 1 && isLetter(remaining[0]) {
+		version.Suffix = strings.ToLower(remaining)
+		return version, nil
+	}
+	remaining = strings.TrimLeft(remaining, ".-_~")
+	version.Prerelease = strings.ToLower(remaining)
+	return version, nil
+}
+
+// MustParseVersion is like ParseVersion but panics if the version is invalid.
+func MustParseVersion(v string) *Version {
+	version, err := ParseVersion(v)
+	if err != nil {
+		panic(err)
+	}
+	return version
+}
+
+// String returns the original version string
+func (v *Version) String() string {
+	return v.Original
+}
+
+// Compare returns -1, 0 or 1 if v is respectively lower than,
+// equal to or greater than other. Build metadata is ignored.
+func (v *Version) Compare(other *Version) int {
+	maxLen := len(v.Segments)
+	if len(other.Segments) > maxLen {
+		maxLen = len(other.Segments)
+	}
+	for i := 0; i < maxLen; i++ {
+		if result := compareInts(segmentAt(v.Segments, i), segmentAt(other.Segments, i)); result != 0 {
+			return result
+		}
+	}
+
+	if result := strings.Compare(v.Suffix, other.Suffix); result != 0 {
+		return result
+	}
+
+	// A release is greater than any of its pre-releases
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// comparePrerelease compares pre-release tags by the rank of their
+// leading word and then by the numbers and words that follow it.
+func comparePrerelease(a, b string) int {
+	aFields := splitPrerelease(a)
+	bFields := splitPrerelease(b)
+
+	if result := compareInts(prereleaseRanks[aFields[0]], prereleaseRanks[bFields[0]]); result != 0 {
+		return result
+	}
+	for i := 0; i < len(aFields) && i < len(bFields); i++ {
+		aNumber, aErr := strconv.Atoi(aFields[i])
+		bNumber, bErr := strconv.Atoi(bFields[i])
+
+		var result int
+		switch {
+		case aErr == nil && bErr == nil:
+			result = compareInts(aNumber, bNumber)
+		case aErr == nil:
+			result = -1
+		case bErr == nil:
+			result = 1
+		default:
+			result = strings.Compare(aFields[i], bFields[i])
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return compareInts(len(aFields), len(bFields))
+}
+
+// splitPrerelease splits a pre-release tag into words and numbers,
+// "beta.2" and "beta2" both becoming ["beta", "2"].
+func splitPrerelease(tag string) []string {
+	var fields []string
+	start := 0
+	for i := 1; i <= len(tag); i++ {
+		if i < len(tag) && isDigit(tag[i]) == isDigit(tag[i-1]) && !isSeparator(tag[i]) && !isSeparator(tag[i-1]) {
+			continue
+		}
+		if field := tag[start:i]; field != "" && !isSeparator(field[0]) {
+			fields = append(fields, field)
+		}
+		start = i
+	}
+	if len(fields) == 0 {
+		return []string{""}
+	}
+	return fields
+}
+
+// CompareVersions compares two version strings and returns -1, 0 or 1
+// if a is respectively lower than, equal to or greater than b.
+// Versions which cannot be parsed are compared by their numeric parts.
+func CompareVersions(a, b string) int {
+	aVersion, aErr := ParseVersion(a)
+	bVersion, bErr := ParseVersion(b)
+	if aErr != nil || bErr != nil {
+		switch {
+		case versionLessNumeric(a, b):
+			return -1
+		case versionLessNumeric(b, a):
+			return 1
+		}
+		return 0
+	}
+	return aVersion.Compare(bVersion)
+}
+
+// Constraint is a set of version ranges, for example ">=4.0 <5.2".
+//
+// Comparisons in a range are separated by spaces or commas and all
+// have to hold, ranges are separated by "||" and any of them has to
+// hold. Supported operators are =, !=, >, >=, <, <=, ~ (same minor
+// version) and ^ (same major version). A version without operator
+// must be equal, and can end with a wildcard like "4.x" or "4.*".
+type Constraint struct {
+	original string
+	ranges   [][]comparison
+}
+
+type comparison struct {
+	operator string
+	version  *Version
+}
+
+// ParseConstraint parses a version constraint.
+func ParseConstraint(constraint string) (*Constraint, error) {
+	parsed := &Constraint{original: constraint}
+
+	for _, part := range strings.Split(constraint, "||") {
+		terms := strings.FieldsFunc(part, func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("invalid constraint: %q", constraint)
+		}
+
+		var comparisons []comparison
+		for i := 0; i < len(terms); i++ {
+			term := terms[i]
+			// Allow a space between an operator and its version
+			if strings.Trim(term, "<>=!~^") == "" && i+1 < len(terms) {
+				term += terms[i+1]
+				i++
+			}
+
+			parsedTerm, err := parseComparisons(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", constraint, err)
+			}
+			comparisons = append(comparisons, parsedTerm...)
+		}
+		parsed.ranges = append(parsed.ranges, comparisons)
+	}
+	return parsed, nil
+}
+
+// parseComparisons parses a single term of a constraint. Terms with
+// wildcards, ~ and ^ are expanded into a lower and upper bound.
+func parseComparisons(term string) ([]comparison, error) {
+	operator := term[:len(term)-len(strings.TrimLeft(term, "<>=!~^"))]
+	value := term[len(operator):]
+	switch operator {
+	case "", "=", "==":
+		operator = "="
+	case "!=", ">", ">=", "<", "<=", "~", "^":
+	default:
+		return nil, fmt.Errorf("unknown operator %q", operator)
+	}
+
+	if trimmed := strings.TrimRight(value, ".xX*"); trimmed != value {
+		if operator != "=" {
+			return nil, fmt.Errorf("wildcards are only supported for equality: %q", term)
+		}
+		return wildcardComparisons(trimmed)
+	}
+
+	version, err := ParseVersion(value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case "~":
+		upper := bumpSegment(version.Segments, 1)
+		return []comparison{{">=", version}, {"<", upper}}, nil
+	case "^":
+		// The first non-zero segment must stay the same
+		index := 0
+		for index < len(version.Segments)-1 && version.Segments[index] == 0 {
+			index++
+		}
+		upper := bumpSegment(version.Segments, index)
+		return []comparison{{">=", version}, {"<", upper}}, nil
+	}
+	return []comparison{{operator, version}}, nil
+}
+
+// wildcardComparisons expands a version like "4.x" into a range.
+func wildcardComparisons(prefix string) ([]comparison, error) {
+	if prefix == "" {
+		// A lone wildcard matches any version
+		return nil, nil
+	}
+	lower, err := ParseVersion(prefix)
+	if err != nil {
+		return nil, err
+	}
+	upper := bumpSegment(lower.Segments, len(lower.Segments)-1)
+	return []comparison{{">=", lower}, {"<", upper}}, nil
+}
+
+// bumpSegment returns the version where the segment at index is
+// incremented and all the following ones are dropped.
+func bumpSegment(segments []int, index int) *Version {
+	if index >= len(segments) {
+		index = len(segments) - 1
+	}
+	bumped := make([]int, index+1)
+	copy(bumped, segments)
+	bumped[index]++
+
+	parts := make([]string, len(bumped))
+	for i, segment := range bumped {
+		parts[i] = strconv.Itoa(segment)
+	}
+	return &Version{Original: strings.Join(parts, "."), Segments: bumped}
+}
+
+// Check reports whether the version satisfies the constraint.
+func (c *Constraint) Check(version *Version) bool {
+	for _, comparisons := range c.ranges {
+		if checkComparisons(comparisons, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckString reports whether the version string satisfies the
+// constraint. Invalid versions never do.
+func (c *Constraint) CheckString(version string) bool {
+	parsed, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+	return c.Check(parsed)
+}
+
+// String returns the original constraint string
+func (c *Constraint) String() string {
+	return c.original
+}
+
+func checkComparisons(comparisons []comparison, version *Version) bool {
+	for _, comparison := range comparisons {
+		result := version.Compare(comparison.version)
+
+		var ok bool
+		switch comparison.operator {
+		case "=":
+			ok = result == 0
+		case "!=":
+			ok = result != 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		case "<":
+			ok = result < 0
+		case "<=":
+			ok = result <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func segmentAt(segments []int, index int) int {
+	if index < len(segments) {
+		return segments[index]
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isSeparator(c byte) bool {
+	return c == '.' || c == '-' || c == '_'
+}
@@ -81,3 +81,119 @@ func TestVersionSelectionInFingerprinting(t *testing.T) {
 		}
 	})
 }
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input      string
+		segments   []int
+		suffix     string
+		prerelease string
+		build      string
+	}{
+		{"1.2.3", []int{1, 2, 3}, "", "", ""},
+		{"v2.0", []int{2, 0}, "", "", ""},
+		{"1.0.2k", []int{1, 0, 2}, "k", "", ""},
+		{"3.1a", []int{3, 1}, "a", "", ""},
+		{"1.9.0b1", []int{1, 9, 0}, "", "b1", ""},
+		{"2.0.0-RC.1", []int{2, 0, 0}, "", "rc.1", ""},
+		{"3.1beta2", []int{3, 1}, "", "beta2", ""},
+		{"1.4.0+build.5", []int{1, 4, 0}, "", "", "build.5"},
+		{"1.2.beta", []int{1, 2}, "", "beta", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			version, err := ParseVersion(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.segments, version.Segments)
+			require.Equal(t, tt.suffix, version.Suffix)
+			require.Equal(t, tt.prerelease, version.Prerelease)
+			require.Equal(t, tt.build, version.Build)
+			require.Equal(t, tt.input, version.String())
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"", "v", "beta", "x.1"} {
+			_, err := ParseVersion(input)
+			require.Error(t, err, "could parse invalid version %q", input)
+		}
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0.0", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3+build.1", "1.2.3+build.2", 0},
+		{"1.2", "1.10", -1},
+		{"1.0.2", "1.0.2k", -1},
+		{"1.0.2k", "1.0.2l", -1},
+		{"1.0.2k", "1.0.3", -1},
+		{"2.0.0-rc1", "2.0.0", -1},
+		{"2.0.0-alpha", "2.0.0-beta", -1},
+		{"2.0.0-beta.2", "2.0.0-beta.10", -1},
+		{"2.0.0-beta", "2.0.0-beta.1", -1},
+		{"2.0.0-rc.1", "2.0.0-beta.5", 1},
+		{"3.1beta2", "3.1rc1", -1},
+		{"3.1", "3.1a", -1},
+		{"3.1a1", "3.1", -1},
+		{"3.1a1", "3.1a", -1},
+		{"3.1a2", "3.1a10", -1},
+		{"3.1a9", "3.1b1", -1},
+		{"3.1b2", "3.1rc1", -1},
+		{"1.9.0b1", "1.9.0", -1},
+		{"abc", "1.0", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			require.Equal(t, tt.expected, CompareVersions(tt.a, tt.b))
+			require.Equal(t, -tt.expected, CompareVersions(tt.b, tt.a))
+		})
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{">=4.0 <5.2", "4.0", true},
+		{">=4.0 <5.2", "5.1.9", true},
+		{">=4.0 <5.2", "5.2", false},
+		{">=4.0 <5.2", "5.2.0-rc1", true},
+		{">=4.0, <5.2", "3.9", false},
+		{">= 4.0 < 5.2", "4.5", true},
+		{"<1.0 || >=2.0", "1.5", false},
+		{"<1.0 || >=2.0", "2.1", true},
+		{"1.2.3", "v1.2.3", true},
+		{"!=1.2.3", "1.2.4", true},
+		{"4.x", "4.9.1", true},
+		{"4.*", "5.0", false},
+		{"*", "0.1", true},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^0.2.3", "0.3.0", false},
+		{"<=1.0.2k", "1.0.2j", true},
+		{">=1.0", "invalid", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint+"_"+tt.version, func(t *testing.T) {
+			constraint, err := ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, constraint.CheckString(tt.version))
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"", ">=", "=>1.0", "<4.x", ">=1.0 ||"} {
+			_, err := ParseConstraint(input)
+			require.Error(t, err, "could parse invalid constraint %q", input)
+		}
+	})
+}
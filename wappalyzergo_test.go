@@ -1,6 +1,11 @@
 package wappalyzer
 
 import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,12 +27,29 @@ func TestCookiesDetect(t *testing.T) {
 			"Set-Cookie": {"path=/; jsessionid=111; path=/, jsessionid=111;"},
 		}, []byte(""))
 		fingerprints1 := wappalyzerClient.Fingerprint(map[string][]string{
-			"Set-Cookie": {"jsessionid=111; path=/, XSRF-TOKEN=; expires=test, path=/ laravel_session=eyJ*"},
+			"Set-Cookie": {"jsessionid=111; path=/, XSRF-TOKEN=; expires=Wed, 21 Oct 2015 07:28:00 GMT; path=/, laravel_session=eyJ*; path=/; HttpOnly"},
 		}, []byte(""))
 
 		require.Equal(t, map[string]struct{}{"Java": {}}, fingerprints, "could not get correct fingerprints")
 		require.Equal(t, map[string]struct{}{"Java": {}, "Laravel": {}, "PHP": {}}, fingerprints1, "could not get correct fingerprints")
 	})
+
+	t.Run("attributes", func(t *testing.T) {
+		wappalyzerClient, _ := New()
+
+		fingerprints := wappalyzerClient.Fingerprint(map[string][]string{
+			"set-cookie": {
+				"session=abc; Path=/; Secure; HttpOnly; jsessionid=111",
+				"theme=dark mode; Expires=Wed, 21 Oct 2015 07:28:00 GMT; laravel_session=1",
+			},
+		}, []byte(""))
+		require.Empty(t, fingerprints, "could get fingerprints from cookie attributes")
+
+		fingerprints = wappalyzerClient.Fingerprint(map[string][]string{
+			"Set-Cookie": {"theme=dark mode; Path=/", "JSESSIONID=111; Path=/; Secure"},
+		}, []byte(""))
+		require.Equal(t, map[string]struct{}{"Java": {}}, fingerprints, "could not get correct fingerprints")
+	})
 }
 
 func TestHeadersDetect(t *testing.T) {
@@ -54,6 +76,15 @@ func TestBodyDetect(t *testing.T) {
 		require.Contains(t, matches, "Mura CMS:1", "Could not get correct match")
 	})
 
+	t.Run("version-case", func(t *testing.T) {
+		matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(`<html>
+<head>
+<meta name="Generator" content="Smartstore.NET 4.1.0-RC1">
+</head>
+</html>`))
+		require.Contains(t, matches, "Smartstore:4.1.0-RC1", "Could not get version with original case")
+	})
+
 	t.Run("html-implied", func(t *testing.T) {
 		matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(`<html data-ng-app="rbschangeapp">
 <head>
@@ -115,3 +146,287 @@ func Test_FingerprintWithInfo(t *testing.T) {
 	require.Equal(t, "Liferay.svg", value.Icon, "could not get correct icon")
 	require.ElementsMatch(t, []string{"CMS"}, value.Categories, "could not get correct categories")
 }
+
+func TestURLDetect(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"Shopify": {"url": ["^https?://[^/]+\\.myshopify\\.com"]},
+		"WordPress": {"url": ["/wp-(?:admin|content)/"], "implies": ["PHP"]},
+		"Versioned": {"url": ["/v([\\d.]+)/app\\;version:\\1"]}
+	}`)
+
+	matches := wappalyzer.FingerprintWithURL("https://example.com/wp-admin/index.php", map[string][]string{}, []byte(""))
+	require.Equal(t, map[string]struct{}{"WordPress": {}, "PHP": {}}, matches, "could not get url match")
+
+	matches = wappalyzer.FingerprintWithURL("https://store.myshopify.com/", map[string][]string{}, []byte(""))
+	require.Equal(t, map[string]struct{}{"Shopify": {}}, matches, "could not get url domain match")
+
+	matches = wappalyzer.FingerprintWithURL("https://example.com/v2.1.0/app", map[string][]string{}, []byte(""))
+	require.Equal(t, map[string]struct{}{"Versioned:2.1.0": {}}, matches, "could not get url version")
+
+	matches = wappalyzer.FingerprintWithURL("", map[string][]string{}, []byte(""))
+	require.Empty(t, matches, "could get match without url")
+}
+
+// newTestWappalyzer creates a wappalyzer with only the provided fingerprints
+func newTestWappalyzer(t *testing.T, apps string) *Wappalyze {
+	filePath := filepath.Join(t.TempDir(), "fingerprints.json")
+	err := os.WriteFile(filePath, []byte(`{"apps": `+apps+`}`), 0o600)
+	require.NoError(t, err, "could not write fingerprints")
+
+	wappalyzer, err := NewFromFile(filePath, false, false)
+	require.NoError(t, err, "could not create wappalyzer")
+	return wappalyzer
+}
+
+func TestDNSDetect(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"Google Workspace": {"dns": {"MX": ["aspmx\\.l\\.google\\.com", "googlemail\\.com"]}},
+		"SendGrid": {"dns": {"TXT": ["include:sendgrid\\.net"]}},
+		"Cloudflare": {"dns": {"NS": ["\\.ns\\.cloudflare\\.com"], "SOA": ["dns\\.cloudflare\\.com"]}}
+	}`)
+
+	matches := wappalyzer.FingerprintDNS(map[string][]string{
+		"mx":    {"10 mx.example.com.", "1 ASPMX.L.GOOGLE.COM."},
+		"TXT":   {"v=spf1 include:sendgrid.net ~all"},
+		"CNAME": {"example.ns.cloudflare.com."},
+	})
+	require.Equal(t, map[string]struct{}{"Google Workspace": {}, "SendGrid": {}}, matches, "could not get dns matches")
+}
+
+func TestRobotsDetect(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"WordPress": {"robots": ["Disallow: /wp-admin/"], "implies": ["PHP"]},
+		"Drupal": {"robots": ["Disallow: /core/"]},
+		"Magento": {"robots": ["Disallow: /checkout/\\nDisallow: /customer/"]}
+	}`)
+
+	matches := wappalyzer.FingerprintRobots([]byte("\ufeffUser-agent: *\r\n# Disallow: /core/\r\n\r\nDisallow:/wp-admin/   # admin\r\nAllow: /wp-admin/admin-ajax.php\r\n"))
+	require.Equal(t, map[string]struct{}{"WordPress": {}, "PHP": {}}, matches, "could not get robots matches")
+
+	matches = wappalyzer.FingerprintRobots([]byte("User-agent: *\n  Disallow:   /checkout/\nDisallow: /customer/\n"))
+	require.Equal(t, map[string]struct{}{"Magento": {}}, matches, "could not get multiline robots match")
+
+	require.Empty(t, wappalyzer.FingerprintRobots([]byte("<html>not found</html>")), "could get matches for invalid robots")
+}
+
+func TestTextDetect(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"Acme CMS": {"text": ["Powered by Acme CMS ([\\d.]+)\\;version:\\1"]},
+		"Widgets": {"text": ["Widgets & Co"]},
+		"Hidden": {"text": ["hidden phrase"]}
+	}`)
+
+	matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(`<html>
+<head>
+<title>hidden phrase</title>
+<style>body:after { content: "hidden phrase"; }</style>
+<script>var footer = "Widgets &amp; Co";</script>
+</head>
+<body>
+<!-- hidden phrase -->
+<div>Main<p>content</p></div>
+<footer>Powered   by <a href="https://acme.example">Acme
+CMS</a> 2.4.1</footer>
+</body>
+</html>`))
+	require.Equal(t, map[string]struct{}{"Acme CMS:2.4.1": {}}, matches, "could not get text matches")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{}, []byte(`<p>&copy; Widgets &amp; Co</p>`))
+	require.Equal(t, map[string]struct{}{"Widgets": {}}, matches, "could not get text match with entities")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{}, []byte(`<body><script></script><footer>Widgets &amp; Co</footer></body>`))
+	require.Equal(t, map[string]struct{}{"Widgets": {}}, matches, "could not get text match after empty script")
+}
+
+func TestCSSDetect(t *testing.T) {
+	wappalyzer, err := New()
+	require.Nil(t, err, "could not create wappalyzer")
+
+	matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(`<html>
+<head>
+<style>.container{--tw-rotate:0;transform:rotate(var(--tw-rotate))}</style>
+</head>
+<body><div style="--radix-popover-trigger-width: 10px">content</div></body>
+</html>`))
+	require.Contains(t, matches, "Tailwind CSS", "could not get style block match")
+	require.Contains(t, matches, "Radix UI", "could not get style attribute match")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{}, []byte(`<p>--tw-rotate</p>`))
+	require.NotContains(t, matches, "Tailwind CSS", "could get css match outside of styles")
+
+	matches = wappalyzer.FingerprintCSS([]byte("body{margin:0}"), []byte(".video-js .vjs-big-play-button{display:block}"))
+	require.Equal(t, map[string]struct{}{"VideoJS": {}}, matches, "could not get stylesheet match")
+}
+
+func TestMetaDetect(t *testing.T) {
+	wappalyzer, err := New()
+	require.Nil(t, err, "could not create wappalyzer")
+
+	t.Run("property", func(t *testing.T) {
+		matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(`<html><head>
+<meta property="og:site_name" content="GitLab">
+<meta property="og:platform" content="PeerTube" />
+</head></html>`))
+		require.Contains(t, matches, "GitLab", "could not get og:site_name match")
+		require.Contains(t, matches, "PeerTube", "could not get og:platform match")
+	})
+
+	t.Run("presence", func(t *testing.T) {
+		matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(`<meta name="pjax-timeout" content="1000">`))
+		require.Contains(t, matches, "jQuery-pjax", "could not get presence-only match")
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(`<meta charset="utf-8" content="GitLab">`))
+		require.NotContains(t, matches, "GitLab", "could get match without key")
+	})
+
+	t.Run("invalid patterns", func(t *testing.T) {
+		wappalyzer := newTestWappalyzer(t, `{"Broken": {"meta": {"generator": ["Broken (?P<x"]}}}`)
+
+		matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(`<meta name="generator" content="WordPress">`))
+		require.Empty(t, matches, "could get match for invalid patterns")
+	})
+}
+
+func TestMetaHTTPEquivDetect(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"Legacy IE": {"meta": {"x-ua-compatible": ["IE=(\\d+)\\;version:\\1"]}}
+	}`)
+
+	matches := wappalyzer.Fingerprint(map[string][]string{}, []byte(`<meta http-equiv="X-UA-Compatible" content="IE=8">`))
+	require.Equal(t, map[string]struct{}{"Legacy IE:8": {}}, matches, "could not get http-equiv match")
+}
+
+func TestParseSetCookie(t *testing.T) {
+	tests := []struct {
+		input string
+		name  string
+		value string
+		ok    bool
+	}{
+		{"session=abc; Path=/; Secure", "session", "abc", true},
+		{" theme = dark mode ; Path=/", "theme", "dark mode", true},
+		{"token=a=b==; HttpOnly", "token", "a=b==", true},
+		{"empty=", "empty", "", true},
+		{"Secure; HttpOnly", "", "", false},
+		{"=value", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			name, value, ok := parseSetCookie(tt.input)
+			require.Equal(t, tt.ok, ok, "could not get correct result")
+			require.Equal(t, tt.name, name, "could not get correct name")
+			require.Equal(t, tt.value, value, "could not get correct value")
+		})
+	}
+
+	require.Equal(t, []string{
+		"a=1; Expires=Wed, 21 Oct 2015 07:28:00 GMT; Path=/",
+		"b=2",
+		"c=3; Secure",
+	}, splitSetCookie("a=1; Expires=Wed, 21 Oct 2015 07:28:00 GMT; Path=/, b=2,c=3; Secure"), "could not split folded cookies")
+}
+
+func TestRequestCookiesDetect(t *testing.T) {
+	wappalyzer, err := New()
+	require.Nil(t, err, "could not create wappalyzer")
+
+	matches := wappalyzer.FingerprintCookieHeader("theme=dark; PHPSESSID=abc123; invalid")
+	require.Equal(t, map[string]struct{}{"PHP": {}}, matches, "could not get cookie header match")
+
+	matches = wappalyzer.FingerprintWithCookies(map[string][]string{
+		"Server": {"Apache/2.4.29"},
+	}, []byte(""), []*http.Cookie{{Name: "JSESSIONID", Value: "111"}})
+	require.Equal(t, map[string]struct{}{"Apache HTTP Server:2.4.29": {}, "Java": {}}, matches, "could not get merged cookie match")
+
+	t.Run("jar", func(t *testing.T) {
+		jar, err := cookiejar.New(nil)
+		require.NoError(t, err, "could not create cookie jar")
+
+		target, err := url.Parse("https://example.com/account")
+		require.NoError(t, err, "could not parse url")
+		jar.SetCookies(target, []*http.Cookie{{Name: "laravel_session", Value: "eyJ", Path: "/"}})
+
+		matches := wappalyzer.FingerprintCookieJar(jar, target)
+		require.Equal(t, map[string]struct{}{"Laravel": {}, "PHP": {}}, matches, "could not get cookie jar match")
+
+		other, err := url.Parse("https://other.example/")
+		require.NoError(t, err, "could not parse url")
+		require.Empty(t, wappalyzer.FingerprintCookieJar(jar, other), "could get cookies of another site")
+	})
+}
+
+func TestKeyPatternsDetect(t *testing.T) {
+	wappalyzer, err := New()
+	require.Nil(t, err, "could not create wappalyzer")
+
+	matches := wappalyzer.Fingerprint(map[string][]string{
+		"Set-Cookie": {"_ga_1A2B3C4D=GS1.1.1700000000; Path=/", "LtpaToken=abc; Path=/"},
+	}, []byte(""))
+	require.Contains(t, matches, "Google Analytics", "could not get wildcard cookie match")
+	require.Contains(t, matches, "WebSphere", "could not get regex cookie match")
+
+	t.Run("headers", func(t *testing.T) {
+		wappalyzer := newTestWappalyzer(t, `{
+			"Edge Platform": {"headers": {"x-edge-*": "", "^x-request-id-[0-9]+$": "v([\\d.]+)\\;version:\\1"}},
+			"Brackets": {"cookies": {"mybb[lastvisit]": ""}}
+		}`)
+
+		matches := wappalyzer.Fingerprint(map[string][]string{
+			"X-Request-Id-42": {"v1.2"},
+			"Set-Cookie":      {"mybb[lastvisit]=1700000000"},
+		}, []byte(""))
+		require.Equal(t, map[string]struct{}{"Edge Platform:1.2": {}, "Brackets": {}}, matches, "could not get key pattern matches")
+
+		matches = wappalyzer.Fingerprint(map[string][]string{"X-Edge-Location": {"fra"}}, []byte(""))
+		require.Equal(t, map[string]struct{}{"Edge Platform": {}}, matches, "could not get wildcard header match")
+
+		matches = wappalyzer.Fingerprint(map[string][]string{"X-Request-Id-42a": {"v1.2"}, "Set-Cookie": {"mybblastvisit=1"}}, []byte(""))
+		require.Empty(t, matches, "could get partial key pattern matches")
+	})
+}
+
+func TestContentTypeDetect(t *testing.T) {
+	wappalyzer := newTestWappalyzer(t, `{
+		"Bootstrap": {"html": ["<div class=\"container\""], "css": ["\\.navbar-toggler"]},
+		"React": {"scripts": ["react\\.production\\.min\\.js"]}
+	}`)
+
+	page := []byte(`<html><head><title>Home</title></head><body><div class="container"></div></body></html>`)
+	matches, title := wappalyzer.FingerprintWithTitle(map[string][]string{"Content-Type": {"text/html; charset=utf-8"}}, page)
+	require.Equal(t, map[string]struct{}{"Bootstrap": {}}, matches, "could not get html match")
+	require.Equal(t, "Home", title, "could not get title")
+
+	matches, title = wappalyzer.FingerprintWithTitle(map[string][]string{}, page)
+	require.Equal(t, map[string]struct{}{"Bootstrap": {}}, matches, "could not get sniffed html match")
+	require.Equal(t, "Home", title, "could not get sniffed title")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{"Content-Type": {"application/javascript"}}, []byte(`/** @license react.production.min.js */ var a='<div class="container"';`))
+	require.Equal(t, map[string]struct{}{"React": {}}, matches, "could not get script match")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{"Content-Type": {"text/css"}}, []byte(`.navbar-toggler{padding:0}`))
+	require.Equal(t, map[string]struct{}{"Bootstrap": {}}, matches, "could not get stylesheet match")
+
+	matches, title = wappalyzer.FingerprintWithTitle(map[string][]string{"Content-Type": {"application/json"}}, []byte(`{"html": "<div class=\"container\"></div>"}`))
+	require.Empty(t, matches, "could get json match")
+	require.Empty(t, title, "could get json title")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{}, []byte(`{"html": "<div class=\"container\"></div>"}`))
+	require.Empty(t, matches, "could get sniffed json match")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{}, append([]byte("\x89PNG\r\n\x1a\n"), page...))
+	require.Empty(t, matches, "could get binary match")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{"Content-Type": {"image/svg+xml"}}, []byte(`<svg xmlns="http://www.w3.org/2000/svg"><div class="container"></div></svg>`))
+	require.Empty(t, matches, "could get svg match")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{"Content-Type": {"application/xml"}}, []byte(`<response><div class="container"></div></response>`))
+	require.Empty(t, matches, "could get xml match")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{}, []byte(`<?xml version="1.0"?><feed><div class="container"></div></feed>`))
+	require.Empty(t, matches, "could get sniffed xml match")
+
+	matches = wappalyzer.Fingerprint(map[string][]string{}, []byte(`<?xml version="1.0"?><html xmlns="http://www.w3.org/1999/xhtml"><body><div class="container"></div></body></html>`))
+	require.Equal(t, map[string]struct{}{"Bootstrap": {}}, matches, "could not get sniffed xhtml match")
+}